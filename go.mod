@@ -0,0 +1,3 @@
+module github.com/cjmxp/poly2tri.go
+
+go 1.18
@@ -7,27 +7,47 @@ const (
 	DT_NODE_CLOSED int = 0x02
 )
 
+// AStar finds paths over the triangles given to Init. Once Init and SetPredicates
+// have returned, the graph is only read: Find, ToPath, ToPath64 and GetTriangleAtPoint
+// keep their scratch state per call and may run from many goroutines at once. A graph
+// attached to a SweepContext changes with the mesh and must not be searched meanwhile.
 type AStar struct {
 	spatials       []*SpatialNode
-	openedList     []*SpatialNode
-	isort          *ISort
 	spatialNodeMap map[*Triangle]*SpatialNode
+	predicates     Predicates
+	grid           *triangleGrid
+	nextOrder      int
 }
 
 func (this *AStar) Init(ts []*Triangle) {
-	this.isort = &ISort{}
 	this.spatials = []*SpatialNode{}
-	this.openedList = []*SpatialNode{}
 	this.spatialNodeMap = make(map[*Triangle]*SpatialNode)
 	for i := 0; i < len(ts); i++ {
 		this.spatials = append(this.spatials, this.getNodeFromTriangle(ts[i]))
 	}
+	this.setOrders()
+	this.grid = newTriangleGrid(this.spatials)
 }
 
+// SetPredicates selects the arithmetic of the point in triangle tests, RobustPredicates by default.
+func (this *AStar) SetPredicates(predicates Predicates) {
+	this.predicates = predicates
+}
+
+// GetTriangleAtPoint returns the node of the triangle holding p, or nil. A point on a side
+// or at a corner shared by several triangles gives the one that joined the graph first,
+// in the order of the triangles given to Init. An attached SweepContext orders them like
+// GetTriangles when it triangulates again, refines, smooths or removes a vertex; the
+// triangles made by its other edits come after, oldest first. A grid over the triangles
+// keeps the search to the few of them around p.
 func (this *AStar) GetTriangleAtPoint(p *Point) *SpatialNode {
-	for i := 0; i < len(this.spatials); i++ {
-		v := this.spatials[i]
-		if v.pointInsideTriangle(p) {
+	if this.grid == nil {
+		return nil
+	}
+	candidates := this.grid.candidates(p)
+	for i := 0; i < len(candidates); i++ {
+		v := candidates[i]
+		if v.pointInsideTriangle(p, this.predicates) {
 			return v
 		}
 	}
@@ -35,65 +55,85 @@ func (this *AStar) GetTriangleAtPoint(p *Point) *SpatialNode {
 }
 
 func (this *AStar) Find(startNode, endNode *SpatialNode) []*SpatialNode {
-	this.reset()
-	currentNode := startNode
-	this.addToOpenedList(startNode)
-	startNode.flags = DT_NODE_OPEN
+	search := newAStarSearch()
+	start := search.get(startNode)
+	currentNode := start
+	search.addToOpenedList(start)
+	start.flags = DT_NODE_OPEN
 	if startNode != nil && endNode != nil {
-		for (currentNode != endNode) && len(this.openedList) > 0 {
-			currentNode = this.getAndRemoveFirstFromOpenedList()
+		for (currentNode.node != endNode) && len(search.openedList) > 0 {
+			currentNode = search.getAndRemoveFirstFromOpenedList()
 			currentNode.flags &= ^DT_NODE_OPEN
 			currentNode.flags |= DT_NODE_CLOSED
-			list := this.getNodeNeighbors(currentNode)
+			list := this.getNodeNeighbors(currentNode.node)
 			for i := 0; i < len(list); i++ {
-				neighborNode := list[i]
 				// Ignore invalid paths and the ones on the closed list.
-				if neighborNode == nil {
+				if list[i] == nil {
 					continue
 				}
+				neighborNode := search.get(list[i])
 				if (neighborNode.flags & DT_NODE_CLOSED) != 0 {
 					continue
 				}
-				g := currentNode.g + neighborNode.distanceToSpatialNode(currentNode)
+				g := currentNode.g + neighborNode.node.distanceToSpatialNode(currentNode.node)
 				//neighborNode.flags = neighborNode.flags & ^DT_NODE_CLOSED
 				// Not in opened list yet.
 				if (neighborNode.flags & DT_NODE_OPEN) == 0 {
 					neighborNode.g = g
-					neighborNode.h = neighborNode.distanceToSpatialNode(endNode)
+					neighborNode.h = neighborNode.node.distanceToSpatialNode(endNode)
 					neighborNode.parent = currentNode
 					neighborNode.flags |= DT_NODE_OPEN
-					this.addToOpenedList(neighborNode)
+					search.addToOpenedList(neighborNode)
 				} else if g < neighborNode.g { // In opened list but with a worse G than this one.
 					neighborNode.g = g
 					neighborNode.parent = currentNode
-					this.Sort()
+					search.Sort()
 				}
 			}
 		}
 	}
-	if currentNode != endNode {
+	if currentNode.node != endNode {
 		panic("Can't find a path")
 	}
 	path := []*SpatialNode{}
-	for currentNode != startNode {
-		path = append(path, currentNode)
+	for currentNode != start {
+		path = append(path, currentNode.node)
 		currentNode = currentNode.parent
 	}
-	path = append(path, currentNode)
+	path = append(path, currentNode.node)
 	return path
 }
 
 func (this *AStar) ToPath(startPoint *Point, endPoint *Point, channel []*SpatialNode) []float32 {
+	path := []float32{}
+	pts := this.toPathPoints(startPoint, endPoint, channel)
+	for i := 0; i < len(pts); i++ {
+		path = append(path, pts[i].X(), pts[i].Y())
+	}
+	return path
+}
+
+// ToPath64 is ToPath keeping the full float64 precision of the points.
+func (this *AStar) ToPath64(startPoint *Point, endPoint *Point, channel []*SpatialNode) []float64 {
+	path := []float64{}
+	pts := this.toPathPoints(startPoint, endPoint, channel)
+	for i := 0; i < len(pts); i++ {
+		path = append(path, pts[i].x, pts[i].y)
+	}
+	return path
+}
+
+func (this *AStar) toPathPoints(startPoint *Point, endPoint *Point, channel []*SpatialNode) []*Point {
 	points := []*Point{}
 	points = append(points, startPoint, startPoint)
 	if len(channel) >= 2 {
 		firstTriangle := channel[len(channel)-1].t
 		secondTriangle := channel[len(channel)-2].t
 		lastTriangle := channel[0].t
-		if !firstTriangle.pointInsideTriangle(startPoint) {
+		if !firstTriangle.pointInsideTriangle(startPoint, this.predicates) {
 			panic("Assert error")
 		}
-		if !lastTriangle.pointInsideTriangle(endPoint) {
+		if !lastTriangle.pointInsideTriangle(endPoint, this.predicates) {
 			panic("Assert error")
 		}
 		startVertex := this.getNotCommonVertex(firstTriangle, secondTriangle)
@@ -117,8 +157,9 @@ func (this *AStar) ToPath(startPoint *Point, endPoint *Point, channel []*Spatial
 	points = append(points, endPoint, endPoint)
 	return this.stringPull(points)
 }
-func (this *AStar) stringPull(_portals []*Point) []float32 {
+func (this *AStar) stringPull(_portals []*Point) []*Point {
 	pts := []*Point{}
+	added := make(map[*Point]bool)
 	apexIndex := 0
 	leftIndex := 0
 	rightIndex := 0
@@ -138,9 +179,9 @@ func (this *AStar) stringPull(_portals []*Point) []float32 {
 				rightIndex = i
 			} else {
 				// Right over left, insert left to path and restart scan from portal left point.
-				if !portalLeft.Activation {
+				if !added[portalLeft] {
 					pts = append(pts, portalLeft)
-					portalLeft.Activation = true
+					added[portalLeft] = true
 				}
 				// Make current left the new apex.
 				portalApex = portalLeft
@@ -164,9 +205,9 @@ func (this *AStar) stringPull(_portals []*Point) []float32 {
 			} else {
 				// Left over right, insert right to path and restart scan from portal right point.
 
-				if !portalRight.Activation {
+				if !added[portalRight] {
 					pts = append(pts, portalRight)
-					portalRight.Activation = true
+					added[portalRight] = true
 				}
 				// Make current right the new apex.
 				portalApex = portalRight
@@ -186,22 +227,17 @@ func (this *AStar) stringPull(_portals []*Point) []float32 {
 		// Append last point to path.
 		pts = append(pts, _portals[len(_portals)-1])
 	}
-	path := []float32{}
-	for i := 0; i < len(pts); i++ {
-		pts[i].Activation = false
-		path = append(path, pts[i].x, pts[i].y)
-	}
-	return path
+	return pts
 }
 func (this *AStar) vequal(a, b *Point) bool {
-	return this.vdistsqr(a, b) < float32(0.001*0.001)
+	return this.vdistsqr(a, b) < 0.001*0.001
 }
-func (this *AStar) vdistsqr(a, b *Point) float32 {
+func (this *AStar) vdistsqr(a, b *Point) float64 {
 	x := b.x - a.x
 	y := b.y - a.y
-	return float32(math.Sqrt(float64(x*x + y*y)))
+	return math.Sqrt(x*x + y*y)
 }
-func (this *AStar) triarea2(a, b, c *Point) float32 {
+func (this *AStar) triarea2(a, b, c *Point) float64 {
 	ax := b.x - a.x
 	ay := b.y - a.y
 	bx := c.x - a.x
@@ -266,35 +302,146 @@ func (this *AStar) getNodeFromTriangle(triangle *Triangle) *SpatialNode {
 	} else {
 		return v
 	}
-	return nil
 }
-func (this *AStar) reset() {
+
+// update rebuilds the graph on ts after the mesh was edited. The triangles that are still
+// there keep their nodes.
+func (this *AStar) update(ts []*Triangle) {
+	nodes := this.spatialNodeMap
+	this.spatials = make([]*SpatialNode, len(ts))
+	this.spatialNodeMap = make(map[*Triangle]*SpatialNode)
+	for i := 0; i < len(ts); i++ {
+		v, ok := nodes[ts[i]]
+		if !ok {
+			v = &SpatialNode{t: ts[i]}
+		}
+		this.spatials[i] = v
+		this.spatialNodeMap[ts[i]] = v
+	}
+	for i := 0; i < len(ts); i++ {
+		this.linkNode(this.spatials[i])
+	}
+	this.setOrders()
+	this.grid = newTriangleGrid(this.spatials)
+}
+
+// patch updates the graph after an edit of the mesh that changed only the triangles of
+// touched, new ones included. The nodes of the triangles that left the interior are
+// dropped, the others are placed again, and their neighbors are linked again. The grid
+// is rebuilt when the graph has doubled or halved since it was last sized.
+func (this *AStar) patch(touched []*Triangle) {
+	seen := make(map[*Triangle]bool)
+	dropped := make(map[*SpatialNode]bool)
+	nodes := []*SpatialNode{}
+	for i := 0; i < len(touched); i++ {
+		t := touched[i]
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		v, ok := this.spatialNodeMap[t]
+		if ok {
+			this.grid.remove(v)
+		}
+		if !t.interior {
+			if ok {
+				delete(this.spatialNodeMap, t)
+				dropped[v] = true
+			}
+			continue
+		}
+		if !ok {
+			v = &SpatialNode{t: t, order: this.nextOrder}
+			this.nextOrder++
+			this.spatialNodeMap[t] = v
+			this.spatials = append(this.spatials, v)
+		}
+		nodes = append(nodes, v)
+	}
+	if len(dropped) > 0 {
+		spatials := []*SpatialNode{}
+		for i := 0; i < len(this.spatials); i++ {
+			if !dropped[this.spatials[i]] {
+				spatials = append(spatials, this.spatials[i])
+			}
+		}
+		this.spatials = spatials
+	}
+	for i := 0; i < len(nodes); i++ {
+		v := nodes[i]
+		this.linkNode(v)
+		for j := 0; j < 3; j++ {
+			if n, ok := this.spatialNodeMap[v.t.neighbors[j]]; ok && !seen[n.t] {
+				this.linkNode(n)
+			}
+		}
+	}
+	if len(this.spatials) > 2*this.grid.nodes || 2*len(this.spatials) < this.grid.nodes {
+		this.grid = newTriangleGrid(this.spatials)
+		return
+	}
+	for i := 0; i < len(nodes); i++ {
+		this.grid.add(nodes[i])
+	}
+}
+
+// linkNode places v at the centroid of its triangle and lists its neighbors again.
+func (this *AStar) linkNode(v *SpatialNode) {
+	tp := v.t.points
+	v.x = (tp[0].x + tp[1].x + tp[2].x) / 3
+	v.y = (tp[0].y + tp[1].y + tp[2].y) / 3
+	v.neighbors = []*SpatialNode{}
+	for j := 0; j < 3; j++ {
+		if n, ok := this.spatialNodeMap[v.t.neighbors[j]]; ok && !v.t.constrained_edge[j] {
+			v.neighbors = append(v.neighbors, n)
+		}
+	}
+}
+
+// setOrders numbers the nodes in the order of spatials.
+func (this *AStar) setOrders() {
 	for i := 0; i < len(this.spatials); i++ {
-		spatial := this.spatials[i]
-		spatial.parent = nil
-		spatial.g = 0
-		spatial.h = 0
-		spatial.flags = 0
+		this.spatials[i].order = i
+	}
+	this.nextOrder = len(this.spatials)
+}
+
+func (this *AStar) getNodeNeighbors(node *SpatialNode) []*SpatialNode {
+	return node.neighbors
+}
+
+// aStarSearch is the scratch state of one Find call.
+type aStarSearch struct {
+	nodes      map[*SpatialNode]*searchNode
+	openedList []*searchNode
+	isort      *ISort
+}
+
+func newAStarSearch() *aStarSearch {
+	return &aStarSearch{nodes: make(map[*SpatialNode]*searchNode), openedList: []*searchNode{}, isort: &ISort{}}
+}
+func (this *aStarSearch) get(node *SpatialNode) *searchNode {
+	if v, ok := this.nodes[node]; ok {
+		return v
 	}
-	this.openedList = this.openedList[:0]
+	v := &searchNode{node: node}
+	this.nodes[node] = v
+	return v
 }
-func (this *AStar) addToOpenedList(node *SpatialNode) {
+func (this *aStarSearch) addToOpenedList(node *searchNode) {
 	this.openedList = append(this.openedList, node)
 	this.Sort()
 }
-func (this *AStar) getAndRemoveFirstFromOpenedList() *SpatialNode {
+func (this *aStarSearch) getAndRemoveFirstFromOpenedList() *searchNode {
 	spatial := this.openedList[0]
 	this.openedList = this.openedList[1:]
 	return spatial
 }
-func (this *AStar) getNodeNeighbors(node *SpatialNode) []*SpatialNode {
-	return node.neighbors
-}
-func (this *AStar) Sort() {
+func (this *aStarSearch) Sort() {
 	this.isort.Data = this.openedList
 	this.isort.Call = func(a, b interface{}) bool {
-		_a := a.(*SpatialNode)
-		_b := b.(*SpatialNode)
+		_a := a.(*searchNode)
+		_b := b.(*searchNode)
 		if _a.g+_a.h < _b.g+_b.h {
 			return true
 		}
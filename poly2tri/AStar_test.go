@@ -0,0 +1,34 @@
+package poly2tri
+
+import "testing"
+
+func TestFindShortestWayAround(t *testing.T) {
+	// Going below the hole is shorter but crosses more triangles, whatever the scale of
+	// the map
+	for _, scale := range []float64{0.001, 1, 1000} {
+		tcx := &SweepContext{}
+		tcx.Init(rectangle(0, 0, scale, scale))
+		tcx.AddHole(rectangle(0.3*scale, 0.3*scale, 0.7*scale, 0.9*scale))
+		for i := 0; i < 10; i++ {
+			tcx.AddPoint(NewPoint64((0.255+0.05*float64(i))*scale, 0.15*scale))
+		}
+		if err := tcx.TriangulateE(); err != nil {
+			t.Fatal(err)
+		}
+		as := &AStar{}
+		as.Init(tcx.GetTriangles())
+		s, e := NewPoint64(0.1*scale, 0.5*scale), NewPoint64(0.9*scale, 0.5*scale)
+		path := as.ToPath64(s, e, as.Find(as.GetTriangleAtPoint(s), as.GetTriangleAtPoint(e)))
+		want := []float64{s.x, s.y, 0.3 * scale, 0.3 * scale, 0.7 * scale, 0.3 * scale, e.x, e.y}
+		if len(path) != len(want) {
+			t.Errorf("scale %v: got path %v, want %v", scale, path, want)
+			continue
+		}
+		for i := 0; i < len(want); i++ {
+			if path[i] != want[i] {
+				t.Errorf("scale %v: got path %v, want %v", scale, path, want)
+				break
+			}
+		}
+	}
+}
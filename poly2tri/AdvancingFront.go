@@ -20,7 +20,7 @@ func (this *AdvancingFront) Init(head, tail *Node) {
 	this.search_node = head
 }
 
-func (this *AdvancingFront) locateNode(x float32) *Node {
+func (this *AdvancingFront) locateNode(x float64) *Node {
 	node := this.search_node
 	if x < node.value {
 		for node = node.prev; node != nil; node = node.prev {
@@ -52,7 +52,7 @@ func (this *AdvancingFront) locatePoint(point *Point) *Node {
 			} else if point == node.next.point {
 				node = node.next
 			} else {
-				panic("poly2tri Invalid AdvancingFront.locatePoint() call")
+				panic(&InternalError{Op: "AdvancingFront.locatePoint()", Points: []*Point{point}})
 			}
 		}
 	} else if px < nx {
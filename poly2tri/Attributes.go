@@ -0,0 +1,109 @@
+package poly2tri
+
+// regionSeed gives its attribute to the triangles around point.
+type regionSeed struct {
+	point     *Point
+	attribute int
+}
+
+// SetRingAttribute gives attribute to the triangles inside a ring: 0 for the contour of
+// Init, then the holes and contours in the order they were added, or the index in the
+// rings of InitRings. The attribute spreads from the sides of the ring to the filled
+// triangles around, up to the constrained edges, so the triangles inside a hole get none.
+func (this *SweepContext) SetRingAttribute(ring, attribute int) {
+	this.attributes[ring] = attribute
+}
+
+// AddRegionSeed gives attribute to the triangle holding p and to the triangles around it,
+// up to the constrained edges. The seeds are applied after the rings, in the order they
+// were added, each one over the attributes given before. A seed outside the filled area
+// is ignored.
+func (this *SweepContext) AddRegionSeed(p *Point, attribute int) {
+	this.seeds = append(this.seeds, &regionSeed{p, attribute})
+}
+
+// spreadAttributes floods the attributes of the rings and the seeds over the triangles.
+func (this *SweepContext) spreadAttributes() {
+	if len(this.triangles) == 0 || (len(this.attributes) == 0 && len(this.seeds) == 0) {
+		return
+	}
+	around := make(map[*Point]*Triangle)
+	for i := 0; i < len(this.maps); i++ {
+		for j := 0; j < 3; j++ {
+			around[this.maps[i].points[j]] = this.maps[i]
+		}
+	}
+	for i := 0; i < len(this.rings); i++ {
+		attribute, ok := this.attributes[i]
+		if !ok {
+			continue
+		}
+		ring := this.rings[i]
+		ccw := ringArea(ring) > 0
+		starts := []*Triangle{}
+		for j := 0; j < len(ring); j++ {
+			chain := this.edgeChain(ring[j], ring[(j+1)%len(ring)], around)
+			for k := 1; k < len(chain); k++ {
+				// The triangle inside is on the left of a counterclockwise ring
+				a, b := chain[k-1], chain[k]
+				if !ccw {
+					a, b = b, a
+				}
+				if t := sideTriangle(around[a], a, b); t != nil {
+					starts = append(starts, t)
+				}
+			}
+		}
+		floodAttribute(starts, attribute)
+	}
+	for i := 0; i < len(this.seeds); i++ {
+		if t := this.locateInterior(this.seeds[i].point); t != nil && t.interior {
+			floodAttribute([]*Triangle{t}, this.seeds[i].attribute)
+		}
+	}
+}
+
+// sideTriangle returns the triangle around a with the side a-b counterclockwise, or nil.
+func sideTriangle(t *Triangle, a, b *Point) *Triangle {
+	star := pointStar(t, a)
+	for i := 0; i < len(star); i++ {
+		if star[i].pointCCW(a) == b {
+			return star[i]
+		}
+	}
+	return nil
+}
+
+// floodAttribute gives attribute to the interior triangles among starts and to the ones
+// reached from them without crossing a constrained edge.
+func floodAttribute(starts []*Triangle, attribute int) {
+	seen := make(map[*Triangle]bool)
+	triangles := []*Triangle{}
+	for i := 0; i < len(starts); i++ {
+		if starts[i].interior && !seen[starts[i]] {
+			seen[starts[i]] = true
+			triangles = append(triangles, starts[i])
+		}
+	}
+	for len(triangles) > 0 {
+		t := triangles[0]
+		triangles = triangles[1:]
+		t.attribute = attribute
+		for i := 0; i < 3; i++ {
+			if n := t.neighbors[i]; n != nil && n.interior && !t.constrained_edge[i] && !seen[n] {
+				seen[n] = true
+				triangles = append(triangles, n)
+			}
+		}
+	}
+}
+
+// ringArea returns the area of the ring, negative when it is clockwise.
+func ringArea(ring []*Point) float64 {
+	area := 0.0
+	for i := 0; i < len(ring); i++ {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		area += a.x*b.y - b.x*a.y
+	}
+	return area / 2
+}
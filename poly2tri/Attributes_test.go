@@ -0,0 +1,56 @@
+package poly2tri
+
+import "testing"
+
+// checkAttributes fails t when a triangle does not have island inside the island square
+// 12,12 18,18, or outer elsewhere.
+func checkAttributes(t *testing.T, triangles []*Triangle, outer, island int) {
+	t.Helper()
+	for i := 0; i < len(triangles); i++ {
+		tp := triangles[i].points
+		x, y := (tp[0].x+tp[1].x+tp[2].x)/3, (tp[0].y+tp[1].y+tp[2].y)/3
+		want := outer
+		if x > 12 && x < 18 && y > 12 && y < 18 {
+			want = island
+		}
+		if got := triangles[i].GetAttribute(); got != want {
+			t.Fatalf("triangle %v has attribute %d, want %d", tp, got, want)
+		}
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	tcx := &SweepContext{}
+	tcx.Init(rectangle(0, 0, 30, 30))
+	tcx.AddHole(rectangle(10, 10, 20, 20))
+	tcx.AddContour(rectangle(12, 12, 18, 18))
+	tcx.SetRingAttribute(0, 1)
+	tcx.SetRingAttribute(1, 9)
+	tcx.SetRingAttribute(2, 3)
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	checkAttributes(t, tcx.GetTriangles(), 1, 3)
+	if err := tcx.InsertPoint(NewPoint64(5, 7)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.InsertPoint(NewPoint64(15, 14)); err != nil {
+		t.Fatal(err)
+	}
+	checkAttributes(t, tcx.GetTriangles(), 1, 3)
+	if err := tcx.Refine(RefineOptions{MinAngle: 25, MaxArea: 2}); err != nil {
+		t.Fatal(err)
+	}
+	checkAttributes(t, tcx.GetTriangles(), 1, 3)
+	// A seed wins over the ring, and a seed in the hole does nothing
+	tcx.AddRegionSeed(NewPoint64(13, 17), 6)
+	tcx.AddRegionSeed(NewPoint64(11, 11), 8)
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	checkAttributes(t, tcx.GetTriangles(), 1, 6)
+	if err := tcx.Refine(RefineOptions{MinAngle: 25, MaxArea: 2}); err != nil {
+		t.Fatal(err)
+	}
+	checkAttributes(t, tcx.GetTriangles(), 1, 6)
+}
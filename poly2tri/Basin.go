@@ -4,7 +4,7 @@ type Basin struct {
 	left_node    *Node
 	bottom_node  *Node
 	right_node   *Node
-	width        float32
+	width        float64
 	left_highest bool
 }
 
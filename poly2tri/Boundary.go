@@ -0,0 +1,51 @@
+package poly2tri
+
+// BoundaryLoops returns the closed loops of points around triangles, like the ones of
+// SweepContext.GetTriangles: the sides that no other triangle of the list shares. The
+// triangles are on the left of the loops, so the outer boundaries are counterclockwise
+// and the boundaries of the holes clockwise. Where two loops touch at a point, each of
+// them goes around its own part of the triangles.
+func BoundaryLoops(triangles []*Triangle) [][]*Point {
+	// owners gives the triangle with the counterclockwise side p-q
+	owners := make(map[[2]*Point]*Triangle)
+	for i := 0; i < len(triangles); i++ {
+		t := triangles[i]
+		for j := 0; j < 3; j++ {
+			owners[[2]*Point{t.points[j], t.points[(j+1)%3]}] = t
+		}
+	}
+	done := make(map[[2]*Point]bool)
+	loops := [][]*Point{}
+	for i := 0; i < len(triangles); i++ {
+		t := triangles[i]
+		for j := 0; j < 3; j++ {
+			side := [2]*Point{t.points[j], t.points[(j+1)%3]}
+			if done[side] || owners[[2]*Point{side[1], side[0]}] != nil {
+				continue
+			}
+			loop := []*Point{}
+			for !done[side] {
+				done[side] = true
+				loop = append(loop, side[0])
+				side = nextBoundarySide(side, owners)
+			}
+			loops = append(loops, loop)
+		}
+	}
+	return loops
+}
+
+// nextBoundarySide returns the boundary side following p-q, turning around q through the
+// triangles until a side from q has no triangle on its right.
+func nextBoundarySide(side [2]*Point, owners map[[2]*Point]*Triangle) [2]*Point {
+	q := side[1]
+	t := owners[side]
+	for {
+		s := t.pointCCW(q)
+		next := owners[[2]*Point{s, q}]
+		if next == nil {
+			return [2]*Point{q, s}
+		}
+		t = next
+	}
+}
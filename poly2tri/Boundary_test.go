@@ -0,0 +1,139 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+// sameCycle tells if loop is ring, starting from any of its points.
+func sameCycle(loop, ring []*Point) bool {
+	if len(loop) != len(ring) {
+		return false
+	}
+	for s := 0; s < len(ring); s++ {
+		same := true
+		for i := 0; i < len(ring) && same; i++ {
+			same = loop[i] == ring[(s+i)%len(ring)]
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLoops fails t when a side of the loops is not a counterclockwise side of one of
+// the triangles.
+func checkLoops(t *testing.T, loops [][]*Point, triangles []*Triangle) {
+	t.Helper()
+	sides := make(map[[2]*Point]bool)
+	for i := 0; i < len(triangles); i++ {
+		tp := triangles[i].points
+		for j := 0; j < 3; j++ {
+			sides[[2]*Point{tp[j], tp[(j+1)%3]}] = true
+		}
+	}
+	for i := 0; i < len(loops); i++ {
+		loop := loops[i]
+		for j := 0; j < len(loop); j++ {
+			p, q := loop[j], loop[(j+1)%len(loop)]
+			if !sides[[2]*Point{p, q}] || sides[[2]*Point{q, p}] {
+				t.Errorf("%v %v is not a boundary side with the triangles on its left", p, q)
+			}
+		}
+	}
+}
+
+// touchingSquares returns the mesh of two squares of 10 touching at 10,10.
+func touchingSquares(t *testing.T) *SweepContext {
+	tcx := &SweepContext{}
+	tcx.InitRings([][]*Point{rectangle(0, 0, 10, 10), rectangle(10, 10, 20, 20)}, FillNonZero)
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	return tcx
+}
+
+func TestBoundaryLoops(t *testing.T) {
+	contour, hole, points := insertionPolygon(7)
+	triangles := triangulated(t, contour, hole, points).GetTriangles()
+	loops := BoundaryLoops(triangles)
+	checkLoops(t, loops, triangles)
+	// The contour is counterclockwise and the hole clockwise, like the triangles see them
+	if len(loops) != 2 {
+		t.Fatalf("got %d loops, want 2", len(loops))
+	}
+	if !(sameCycle(loops[0], contour) && sameCycle(loops[1], hole)) && !(sameCycle(loops[1], contour) && sameCycle(loops[0], hole)) {
+		t.Errorf("got loops %v, want the contour and the hole", loops)
+	}
+	triangles = touchingSquares(t).GetTriangles()
+	loops = BoundaryLoops(triangles)
+	checkLoops(t, loops, triangles)
+	// Each loop goes around its own square, through the corner once
+	if len(loops) != 2 {
+		t.Fatalf("got %d loops, want 2", len(loops))
+	}
+	for i := 0; i < len(loops); i++ {
+		loop := loops[i]
+		corners := 0
+		for j := 0; j < len(loop); j++ {
+			if loop[j].x == 10 && loop[j].y == 10 {
+				corners++
+			}
+		}
+		if len(loop) != 4 || corners != 1 || math.Abs(ringArea(loop)-100) > 1e-9 {
+			t.Errorf("got loop %v of area %v, want a counterclockwise square of 100", loop, ringArea(loop))
+		}
+	}
+}
+
+func TestVoronoiCells(t *testing.T) {
+	contour, hole, points := insertionPolygon(7)
+	tcx := triangulated(t, contour, hole, points)
+	cells, err := tcx.VoronoiCells()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != len(contour)+len(hole)+len(points) {
+		t.Errorf("got %d cells, want %d", len(cells), len(contour)+len(hole)+len(points))
+	}
+	area := 0.0
+	for i := 0; i < len(cells); i++ {
+		for j := 0; j < len(cells[i].Rings); j++ {
+			area += ringArea(cells[i].Rings[j])
+		}
+	}
+	if want := meshArea(tcx.GetTriangles()); math.Abs(area-want) > 1e-6 {
+		t.Errorf("got area %v, want the %v of the mesh", area, want)
+	}
+	// The cell of the corner is cut in two by the gap between the squares
+	cells, err = touchingSquares(t).VoronoiCells()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != 7 {
+		t.Fatalf("got %d cells, want 7", len(cells))
+	}
+	area = 0.0
+	for i := 0; i < len(cells); i++ {
+		c := cells[i]
+		want := 1
+		if c.Site.x == 10 && c.Site.y == 10 {
+			want = 2
+		}
+		if len(c.Rings) != want {
+			t.Errorf("the cell of %v has %d rings, want %d", c.Site, len(c.Rings), want)
+			continue
+		}
+		for j := 0; j < len(c.Rings); j++ {
+			a := ringArea(c.Rings[j])
+			if math.Abs(a-25) > 1e-9 {
+				t.Errorf("a ring of the cell of %v has area %v, want 25", c.Site, a)
+			}
+			area += a
+		}
+	}
+	if math.Abs(area-200) > 1e-9 {
+		t.Errorf("got area %v, want 200", area)
+	}
+}
@@ -0,0 +1,96 @@
+package poly2tri
+
+import (
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// rectangle returns the corners of x0,y0 x1,y1, counterclockwise.
+func rectangle(x0, y0, x1, y1 float64) []*Point {
+	return []*Point{NewPoint64(x0, y0), NewPoint64(x1, y0), NewPoint64(x1, y1), NewPoint64(x0, y1)}
+}
+
+// concurrencyInput returns a contour, a hole and Steiner points shared by the tests below.
+func concurrencyInput() ([]*Point, []*Point, []*Point) {
+	contour := []*Point{}
+	for i := 0; i < 20; i++ {
+		contour = append(contour, NewPoint64(float64(i*10), 0))
+	}
+	contour = append(contour, NewPoint64(200, 200), NewPoint64(0, 200))
+	hole := rectangle(50, 50, 60, 150)
+	r := rand.New(rand.NewSource(1))
+	steiner := []*Point{}
+	for i := 0; i < 50; i++ {
+		steiner = append(steiner, NewPoint64(float64(r.Intn(190)+5)+0.5, float64(r.Intn(190)+5)+0.5))
+	}
+	return contour, hole, steiner
+}
+
+func triangulateShared(contour, hole, steiner []*Point) ([]*Triangle, error) {
+	tcx := &SweepContext{}
+	tcx.Init(contour)
+	tcx.AddHole(hole)
+	tcx.AddPoints(steiner)
+	err := tcx.TriangulateE()
+	return tcx.GetTriangles(), err
+}
+
+func TestConcurrentTriangulation(t *testing.T) {
+	contour, hole, steiner := concurrencyInput()
+	want, err := triangulateShared(contour, hole, steiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			got, err := triangulateShared(contour, hole, steiner)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(got) != len(want) {
+				t.Errorf("got %d triangles, want %d", len(got), len(want))
+			}
+			SaveOBJ(filepath.Join(dir, strconv.Itoa(g)+".obj"), got)
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentPathfinding(t *testing.T) {
+	contour, hole, steiner := concurrencyInput()
+	triangles, err := triangulateShared(contour, hole, steiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	as := &AStar{}
+	as.Init(triangles)
+	s, e := NewPoint64(5, 100), NewPoint64(190, 100)
+	want := as.ToPath64(s, e, as.Find(as.GetTriangleAtPoint(s), as.GetTriangleAtPoint(e)))
+	if len(want) < 6 {
+		t.Fatalf("path %v does not go around the hole", want)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 20; k++ {
+				got := as.ToPath64(s, e, as.Find(as.GetTriangleAtPoint(s), as.GetTriangleAtPoint(e)))
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("got path %v, want %v", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
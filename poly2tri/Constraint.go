@@ -0,0 +1,231 @@
+package poly2tri
+
+// InsertConstraint adds the constrained edge p-q to the triangulation, flipping the
+// triangles it crosses like the edges of the input. An end that is not a vertex of the
+// mesh is inserted first like with InsertPoint. The edge then belongs to the input, so
+// triangulating again keeps it.
+//
+// The error is an *IntersectingConstraintsError when p-q crosses another constrained
+// edge, or an *OutsidePointError when it leaves the triangulated area. The ends may
+// already have been inserted when an error is returned.
+func (this *SweepContext) InsertConstraint(p, q *Point) (err error) {
+	if len(this.triangles) == 0 {
+		return &InternalError{Op: "SweepContext.InsertConstraint() (not triangulated)"}
+	}
+	if p.equals(q) {
+		return &DuplicatePointError{Points: []*Point{p, q}, Indices: this.indicesOf([]*Point{p, q})}
+	}
+	touched := []*Triangle{}
+	defer func() {
+		if r := recover(); r != nil {
+			err = this.recoverError(r)
+		}
+		this.updateTriangles(touched, err)
+	}()
+	var pStar, qStar []*Triangle
+	if p, pStar, err = this.meshVertex(p); err != nil {
+		return err
+	}
+	touched = append(touched, pStar...)
+	if q, qStar, err = this.meshVertex(q); err != nil {
+		return err
+	}
+	touched = append(touched, qStar...)
+	t := this.vertexTriangle(p, pStar[0])
+	chain, crossed, err := this.traceSegment(p, q, t)
+	if err != nil {
+		return err
+	}
+	// The edge events only reshape the crossed triangles
+	touched = append(touched, crossed...)
+	for i := 1; i < len(chain); i++ {
+		a, b := chain[i-1], chain[i]
+		this.edge_event.constrained_edge = &Edge{p: b, q: a}
+		this.edge_event.right = b.x > a.x
+		this.sweep.edgeEventByPoints(this, b, a, this.wedgeTriangle(a, b, t), a)
+		// Go on from a triangle with the side a-b, so with b
+		t = this.wedgeTriangle(a, b, this.vertexTriangle(a, t))
+	}
+	touched = append(touched, this.legalizeTriangles(crossed)...)
+	for i := 0; i < len(this.edge_list); i++ {
+		e := this.edge_list[i]
+		if (e.p == p && e.q == q) || (e.p == q && e.q == p) {
+			return nil
+		}
+	}
+	edge := NewEdge(p, q)
+	this.edge_list = append(this.edge_list, edge)
+	this.point_edges[edge.q] = append(this.point_edges[edge.q], edge)
+	return nil
+}
+
+// RemoveConstraint removes the constrained edge p-q, which may have been split in
+// several pieces, and flips the triangles around it until they are Delaunay again. Only
+// edges inside the triangulated area can be removed, not the contour or the holes.
+func (this *SweepContext) RemoveConstraint(p, q *Point) (err error) {
+	if len(this.triangles) == 0 {
+		return &InternalError{Op: "SweepContext.RemoveConstraint() (not triangulated)"}
+	}
+	touched := []*Triangle{}
+	defer func() {
+		if r := recover(); r != nil {
+			err = this.recoverError(r)
+		}
+		this.updateTriangles(touched, err)
+	}()
+	missing := &InternalError{Op: "SweepContext.RemoveConstraint() (no such constraint)", Points: []*Point{p, q}, Indices: this.indicesOf([]*Point{p, q})}
+	a, t := this.vertexAt(p)
+	b, _ := this.vertexAt(q)
+	if a == nil || b == nil || a == b {
+		return missing
+	}
+	// The constraint follows sides all the way, so each triangle of the trace has a piece
+	chain, sides, e := this.traceSegment(a, b, t)
+	if e != nil || len(sides) != len(chain)-1 {
+		return missing
+	}
+	for i := 1; i < len(chain); i++ {
+		t := sides[i-1]
+		j := t.edgeIndex(chain[i-1], chain[i])
+		if j < 0 {
+			return missing
+		}
+		if ot := t.neighbors[j]; !t.constrained_edge[j] || ot == nil || !t.interior || !ot.interior {
+			return missing
+		}
+	}
+	points := make(map[*Point]bool)
+	freed := []*Triangle{}
+	for i := 1; i < len(chain); i++ {
+		t := sides[i-1]
+		j := t.edgeIndex(chain[i-1], chain[i])
+		ot := t.neighbors[j]
+		t.constrained_edge[j] = false
+		ot.constrained_edge[ot.edgeIndex(chain[i-1], chain[i])] = false
+		this.setSideLabel(chain[i-1], chain[i], 0)
+		points[chain[i-1]], points[chain[i]] = true, true
+		freed = append(freed, t, ot)
+	}
+	touched = append(freed, this.legalizeTriangles(freed)...)
+	this.removeEdges(points)
+	return nil
+}
+
+// removeEdges drops from the input the edges with both ends in points.
+func (this *SweepContext) removeEdges(points map[*Point]bool) {
+	edges := []*Edge{}
+	for i := 0; i < len(this.edge_list); i++ {
+		if e := this.edge_list[i]; !points[e.p] || !points[e.q] {
+			edges = append(edges, e)
+		}
+	}
+	this.edge_list = edges
+	for p := range points {
+		list := []*Edge{}
+		for i := 0; i < len(this.point_edges[p]); i++ {
+			if e := this.point_edges[p][i]; !points[e.p] || !points[e.q] {
+				list = append(list, e)
+			}
+		}
+		if len(list) > 0 {
+			this.point_edges[p] = list
+		} else {
+			delete(this.point_edges, p)
+		}
+	}
+}
+
+// vertexAt returns the vertex of the mesh at the place of p and a triangle with it, or nil.
+func (this *SweepContext) vertexAt(p *Point) (*Point, *Triangle) {
+	if t := this.locateInterior(p); t != nil {
+		for i := 0; i < 3; i++ {
+			if p.equals(t.points[i]) {
+				return t.points[i], t
+			}
+		}
+	}
+	return nil, nil
+}
+
+// meshVertex returns the vertex of the mesh at the place of p, inserting p when there is
+// none, and the triangles around it that the insertion changed, or a triangle with it
+// when it was there already. The vertex becomes part of the input.
+func (this *SweepContext) meshVertex(p *Point) (*Point, []*Triangle, error) {
+	if v, t := this.vertexAt(p); v != nil {
+		if _, ok := this.indices[v]; !ok {
+			this.AddPoint(v)
+		}
+		return v, []*Triangle{t}, nil
+	}
+	star, err := this.insertPoint(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, star, nil
+}
+
+// traceSegment returns the vertices of the mesh lying on p-q, from p to q, p and q being
+// vertices too, and the triangles on the way: the one along each side p-q follows and
+// the ones it crosses. t is a triangle with p. The error tells when p-q crosses a
+// constrained edge or leaves the triangulated area.
+func (this *SweepContext) traceSegment(p, q *Point, t *Triangle) ([]*Point, []*Triangle, error) {
+	chain := []*Point{p}
+	triangles := []*Triangle{}
+	for a := p; a != q; {
+		if t = this.wedgeTriangle(a, q, t); t == nil {
+			return nil, nil, &InternalError{Op: "SweepContext.traceSegment()", Points: []*Point{p, q}, Indices: this.indicesOf([]*Point{p, q})}
+		}
+		triangles = append(triangles, t)
+		r, l := t.pointCCW(a), t.pointCW(a)
+		if r == q || l == q || this.predicates.orientation(a, r, q) == 0 || this.predicates.orientation(a, l, q) == 0 {
+			// The segment follows the side a-r or a-l
+			if r != q && this.predicates.orientation(a, r, q) != 0 {
+				r = l
+			}
+			a = r
+			chain = append(chain, a)
+			continue
+		}
+		if !t.interior {
+			return nil, nil, &OutsidePointError{Points: []*Point{p, q}, Indices: this.indicesOf([]*Point{p, q})}
+		}
+		// Cross the triangles until the segment reaches a vertex
+		for {
+			i := t.edgeIndex(r, l)
+			if t.constrained_edge[i] {
+				return nil, nil, &IntersectingConstraintsError{Points: []*Point{p, q, r, l}, Indices: this.indicesOf([]*Point{p, q, r, l})}
+			}
+			ot := t.neighbors[i]
+			o := ot.points[ot.edgeIndex(r, l)]
+			t = ot
+			triangles = append(triangles, t)
+			if o == q || this.predicates.orientation(p, q, o) == 0 {
+				a = o
+				chain = append(chain, a)
+				break
+			}
+			if this.predicates.orientation(p, q, o) > 0 {
+				l = o
+			} else {
+				r = o
+			}
+		}
+	}
+	return chain, triangles, nil
+}
+
+// wedgeTriangle returns the triangle around a, t being one of them, whose angle at a
+// holds the direction of q, or nil.
+func (this *SweepContext) wedgeTriangle(a, q *Point, t *Triangle) *Triangle {
+	if t == nil {
+		return nil
+	}
+	star := pointStar(t, a)
+	for i := 0; i < len(star); i++ {
+		s := star[i]
+		if this.predicates.orientation(a, s.pointCCW(a), q) >= 0 && this.predicates.orientation(a, s.pointCW(a), q) <= 0 {
+			return s
+		}
+	}
+	return nil
+}
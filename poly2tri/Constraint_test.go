@@ -0,0 +1,69 @@
+package poly2tri
+
+import "testing"
+
+// sameGraph fails t when the graph as does not match a graph built afresh on triangles.
+func sameGraph(t *testing.T, as *AStar, triangles []*Triangle) {
+	t.Helper()
+	fresh := &AStar{}
+	fresh.Init(triangles)
+	if len(as.spatials) != len(fresh.spatials) {
+		t.Fatalf("got %d nodes, want %d", len(as.spatials), len(fresh.spatials))
+	}
+	for i := 0; i < len(triangles); i++ {
+		tr := triangles[i]
+		v, w := as.spatialNodeMap[tr], fresh.spatialNodeMap[tr]
+		if v == nil {
+			t.Fatalf("triangle %v has no node", tr.points)
+		}
+		if v.x != w.x || v.y != w.y || len(v.neighbors) != len(w.neighbors) {
+			t.Fatalf("node of %v does not match", tr.points)
+		}
+		neighbors := make(map[*Triangle]bool)
+		for j := 0; j < len(w.neighbors); j++ {
+			neighbors[w.neighbors[j].t] = true
+		}
+		for j := 0; j < len(v.neighbors); j++ {
+			if !neighbors[v.neighbors[j].t] {
+				t.Fatalf("node of %v has a wrong neighbor %v", tr.points, v.neighbors[j].t.points)
+			}
+		}
+		c := NewPoint64(v.x, v.y)
+		if n := as.GetTriangleAtPoint(c); n == nil || n.t != tr {
+			t.Fatalf("the centroid of %v is not found in it", tr.points)
+		}
+	}
+}
+
+func TestInsertConstraint(t *testing.T) {
+	contour, hole, points := insertionPolygon(4)
+	tcx := triangulated(t, contour, hole, points)
+	as := &AStar{}
+	tcx.AttachAStar(as)
+	a, b := NewPoint64(5, 10), NewPoint64(95, 12)
+	c, d := NewPoint64(60, 30), NewPoint64(20, 80)
+	if err := tcx.InsertConstraint(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.InsertConstraint(c, d); err != nil {
+		t.Fatal(err)
+	}
+	checkMesh(t, tcx.GetTriangles())
+	sameGraph(t, as, tcx.GetTriangles())
+	// The ends of a failed constraint stay in the mesh
+	e, f := NewPoint64(10, 5), NewPoint64(12, 20)
+	if err := tcx.InsertConstraint(e, f); err == nil {
+		t.Error("a constraint crossing another one was inserted")
+	} else if _, ok := err.(*IntersectingConstraintsError); !ok {
+		t.Errorf("got %T %v, want *IntersectingConstraintsError", err, err)
+	}
+	// Without the constraints, the mesh is the one of their ends
+	if err := tcx.RemoveConstraint(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.RemoveConstraint(c, d); err != nil {
+		t.Fatal(err)
+	}
+	sameGraph(t, as, tcx.GetTriangles())
+	sameMesh(t, tcx.GetTriangles(), triangulated(t, contour, hole, append(points, a, b, c, d, e, f)).GetTriangles())
+}
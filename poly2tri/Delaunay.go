@@ -0,0 +1,152 @@
+package poly2tri
+
+// DelaunayPoints returns the Delaunay triangulation of points, covering their whole
+// convex hull. The sides of the hull are marked as constrained edges, like the border
+// of a polygon. It returns no triangle when all points are collinear, and a
+// *DuplicatePointError when two points are equal.
+func DelaunayPoints(points []*Point) (triangles []*Triangle, err error) {
+	tcx := &SweepContext{}
+	tcx.Init(nil)
+	tcx.AddPoints(points)
+	if !tcx.predicates.hasArea(points) {
+		return []*Triangle{}, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			triangles, err = nil, tcx.recoverError(r)
+		}
+	}()
+	tcx.sweep.sweep(tcx)
+	tcx.finalizationHull()
+	return tcx.GetTriangles(), nil
+}
+
+// hasArea tells if the points are not all on one line.
+func (this Predicates) hasArea(points []*Point) bool {
+	for i := 1; i < len(points); i++ {
+		if !points[i].equals(points[0]) {
+			for j := i + 1; j < len(points); j++ {
+				if this.orientation(points[0], points[i], points[j]) != 0 {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// finalizationHull turns the swept mesh into the Delaunay triangulation of the convex
+// hull of the input: the dents of the front are filled first, then the head and tail
+// points are removed from the hull of input, head and tail.
+func (this *SweepContext) finalizationHull() {
+	chain := []*hullVertex{}
+	for node := this.front.head; node != nil; node = node.next {
+		v := &hullVertex{node.point, nil}
+		if node.prev != nil {
+			v.across = this.borderTriangle(node.prev.point, node.point, node.prev.triangle)
+		}
+		chain = append(chain, v)
+	}
+	this.fillChain(chain)
+	this.removeSuperPoint(this.head)
+	this.removeSuperPoint(this.tail)
+	for i := 0; i < len(this.maps); i++ {
+		t := this.maps[i]
+		for j := 0; j < 3; j++ {
+			if t.neighbors[j] == nil {
+				t.constrained_edge[j] = true
+			}
+		}
+	}
+}
+
+// hullVertex is a point of a chain on the border of the mesh, with the triangle beyond
+// the side from the previous point of the chain.
+type hullVertex struct {
+	point  *Point
+	across *Triangle
+}
+
+// fillChain adds the triangles between a chain of border points and its convex hull,
+// Graham scan like, then makes the mesh Delaunay again. The mesh lies on the right of
+// the chain, which has to be monotone or sorted around a point for the new triangles not
+// to overlap the mesh.
+func (this *SweepContext) fillChain(chain []*hullVertex) {
+	stack := []*hullVertex{chain[0]}
+	for i := 1; i < len(chain); i++ {
+		v := &hullVertex{chain[i].point, chain[i].across}
+		for len(stack) >= 2 {
+			a, b := stack[len(stack)-2], stack[len(stack)-1]
+			if this.predicates.orientation(a.point, b.point, v.point) <= 0 {
+				break
+			}
+			t := NewTriangle(a.point, b.point, v.point)
+			if b.across != nil {
+				t.markNeighbor(b.across)
+			}
+			if v.across != nil {
+				t.markNeighbor(v.across)
+			}
+			this.maps = append(this.maps, t)
+			stack = stack[:len(stack)-1]
+			v.across = t
+		}
+		stack = append(stack, v)
+	}
+	for i := 0; i < len(this.maps); i++ {
+		this.maps[i].interior = true
+	}
+	this.triangles = append([]*Triangle{}, this.maps...)
+	this.legalizeAll()
+}
+
+// borderTriangle returns the triangle with the side p-q on the border of the mesh, t if it is.
+func (this *SweepContext) borderTriangle(p, q *Point, t *Triangle) *Triangle {
+	if t != nil && t.edgeIndex(p, q) >= 0 {
+		return t
+	}
+	for i := 0; i < len(this.maps); i++ {
+		if j := this.maps[i].edgeIndex(p, q); j >= 0 && this.maps[i].neighbors[j] == nil {
+			return this.maps[i]
+		}
+	}
+	return nil
+}
+
+// removeSuperPoint removes s, a point of the convex hull of the Delaunay mesh, and fills
+// the chain of points around it back to the hull of the other points.
+func (this *SweepContext) removeSuperPoint(s *Point) {
+	var t *Triangle
+	for i := 0; i < len(this.maps) && t == nil; i++ {
+		if this.maps[i].containsPoint(s) {
+			t = this.maps[i]
+		}
+	}
+	if t == nil {
+		return
+	}
+	// Go back to the first triangle counterclockwise around s
+	for start, n := t, t.neighborCCW(s); n != nil && n != start; n = t.neighborCCW(s) {
+		t = n
+	}
+	removed := make(map[*Triangle]bool)
+	chain := []*hullVertex{{t.pointCCW(s), nil}}
+	for ; t != nil && !removed[t]; t = t.neighborCW(s) {
+		removed[t] = true
+		a, b := t.pointCCW(s), t.pointCW(s)
+		across := t.neighborAcross(s)
+		if across != nil {
+			across.markNeighborPointers(a, b, nil)
+		}
+		chain = append(chain, &hullVertex{b, across})
+	}
+	maps := []*Triangle{}
+	for i := 0; i < len(this.maps); i++ {
+		if !removed[this.maps[i]] {
+			maps = append(maps, this.maps[i])
+		}
+	}
+	this.maps = maps
+	this.fillChain(chain)
+}
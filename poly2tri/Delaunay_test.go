@@ -0,0 +1,36 @@
+package poly2tri
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDelaunayPoints(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := rectangle(0, 0, 100, 50)
+	for i := 0; i < 200; i++ {
+		points = append(points, NewPoint64(r.Float64()*100, r.Float64()*50))
+	}
+	triangles, err := DelaunayPoints(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMesh(t, triangles)
+	if area := meshArea(triangles); math.Abs(area-5000) > 1e-9 {
+		t.Errorf("got area %v, want the 5000 of the hull", area)
+	}
+	if n := countNotDelaunay(triangles); n > 0 {
+		t.Errorf("%d points are inside the circumcircle of a triangle", n)
+	}
+	if len(ToIndexedMesh(triangles, false).Points) != len(points) {
+		t.Errorf("the triangles do not use every point")
+	}
+}
+
+func TestDelaunayCollinearPoints(t *testing.T) {
+	triangles, err := DelaunayPoints([]*Point{NewPoint64(0, 0), NewPoint64(1, 1), NewPoint64(2, 2)})
+	if err != nil || len(triangles) != 0 {
+		t.Errorf("got %d triangles and %v, want none", len(triangles), err)
+	}
+}
@@ -23,14 +23,9 @@ func (this *Edge) Init(p1, p2 *Point) {
 			this.q = p1
 			this.p = p2
 		} else if p1.x == p2.x {
-			panic("poly2tri Invalid Edge constructor: repeated points!")
+			panic(&DuplicatePointError{Points: []*Point{p1, p2}})
 		}
 	}
-
-	if this.q.p2t_edge_list == nil {
-		this.q.p2t_edge_list = []*Edge{}
-	}
-	this.q.p2t_edge_list = append(this.q.p2t_edge_list, this)
 }
 func (this *Edge) hasPoint(point *Point) bool {
 	return (this.p.x == point.x && this.p.y == point.y) || (this.q.x == point.x && this.q.y == point.y)
@@ -0,0 +1,131 @@
+package poly2tri
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IntersectingConstraintsError reports two constrained edges that cross each other.
+type IntersectingConstraintsError struct {
+	Points  []*Point
+	Indices []int
+}
+
+func (this *IntersectingConstraintsError) Error() string {
+	return "poly2tri Intersecting Constraints " + describePoints(this.Points, this.Indices)
+}
+
+// CollinearEdgeError reports a point lying on a constrained edge that could not be handled.
+type CollinearEdgeError struct {
+	Points  []*Point
+	Indices []int
+}
+
+func (this *CollinearEdgeError) Error() string {
+	return "poly2tri EdgeEvent: Collinear not supported! " + describePoints(this.Points, this.Indices)
+}
+
+// DuplicatePointError reports two input points with the same coordinates.
+type DuplicatePointError struct {
+	Points  []*Point
+	Indices []int
+}
+
+func (this *DuplicatePointError) Error() string {
+	return "poly2tri Repeated points! " + describePoints(this.Points, this.Indices)
+}
+
+// FlipFailedError reports an edge event that ran out of triangles to flip.
+type FlipFailedError struct {
+	Points  []*Point
+	Indices []int
+}
+
+func (this *FlipFailedError) Error() string {
+	return "poly2tri FLIP failed due to missing triangle! " + describePoints(this.Points, this.Indices)
+}
+
+// OutsidePointError reports a point or a constraint added to a triangulation outside of its area.
+type OutsidePointError struct {
+	Points  []*Point
+	Indices []int
+}
+
+func (this *OutsidePointError) Error() string {
+	return "poly2tri Point outside the triangulation! " + describePoints(this.Points, this.Indices)
+}
+
+// InternalError reports an inconsistent mesh, usually caused by degenerate input.
+type InternalError struct {
+	Op      string
+	Points  []*Point
+	Indices []int
+}
+
+func (this *InternalError) Error() string {
+	return "poly2tri Invalid " + this.Op + " call " + describePoints(this.Points, this.Indices)
+}
+
+// OptionError reports an option out of its range, like RefineOptions.MinAngle above
+// MaxMinAngle. Nothing was changed.
+type OptionError struct {
+	Op     string
+	Option string
+	Reason string
+}
+
+func (this *OptionError) Error() string {
+	return "poly2tri Invalid option! " + this.Op + " " + this.Option + " " + this.Reason
+}
+
+// SteinerLimitError reports a refinement stopped by RefineOptions.MaxSteinerPoints.
+type SteinerLimitError struct {
+	Limit int
+}
+
+func (this *SteinerLimitError) Error() string {
+	return "poly2tri Steiner point limit reached! " + strconv.Itoa(this.Limit)
+}
+
+// describePoints formats points as "x,y(#index)" separated by spaces.
+func describePoints(points []*Point, indices []int) string {
+	list := []string{}
+	for i := 0; i < len(points); i++ {
+		str := "nil"
+		if points[i] != nil {
+			str = points[i].toString()
+		}
+		if i < len(indices) && indices[i] >= 0 {
+			str += "(#" + strconv.Itoa(indices[i]) + ")"
+		}
+		list = append(list, str)
+	}
+	return strings.Join(list, " ")
+}
+
+// recoverError turns a panic of the triangulation into one of the error types above.
+// The other panics, runtime errors included, come from bugs of the library and are
+// raised again.
+func (this *SweepContext) recoverError(r interface{}) error {
+	switch e := r.(type) {
+	case *IntersectingConstraintsError:
+		e.Indices = this.indicesOf(e.Points)
+		return e
+	case *CollinearEdgeError:
+		e.Indices = this.indicesOf(e.Points)
+		return e
+	case *DuplicatePointError:
+		e.Indices = this.indicesOf(e.Points)
+		return e
+	case *FlipFailedError:
+		e.Indices = this.indicesOf(e.Points)
+		return e
+	case *OutsidePointError:
+		e.Indices = this.indicesOf(e.Points)
+		return e
+	case *InternalError:
+		e.Indices = this.indicesOf(e.Points)
+		return e
+	}
+	panic(r)
+}
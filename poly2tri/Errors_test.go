@@ -0,0 +1,80 @@
+package poly2tri
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDuplicatePointError(t *testing.T) {
+	tcx := &SweepContext{}
+	tcx.Init([]*Point{NewPoint64(0, 0), NewPoint64(10, 0), NewPoint64(10, 0), NewPoint64(0, 10)})
+	err := tcx.TriangulateE()
+	e, ok := err.(*DuplicatePointError)
+	if !ok {
+		t.Fatalf("got %T %v, want *DuplicatePointError", err, err)
+	}
+	if !reflect.DeepEqual(e.Indices, []int{1, 2}) {
+		t.Errorf("got indices %v, want [1 2]", e.Indices)
+	}
+}
+
+func TestDuplicateSteinerPointError(t *testing.T) {
+	tcx := &SweepContext{}
+	tcx.Init(rectangle(0, 0, 10, 10))
+	tcx.AddPoint(NewPoint64(10, 10))
+	err := tcx.TriangulateE()
+	e, ok := err.(*DuplicatePointError)
+	if !ok {
+		t.Fatalf("got %T %v, want *DuplicatePointError", err, err)
+	}
+	if len(e.Indices) != 2 || e.Indices[0]+e.Indices[1] != 2+4 {
+		t.Errorf("got indices %v, want 2 and 4", e.Indices)
+	}
+}
+
+func TestIntersectingConstraintsError(t *testing.T) {
+	tcx := &SweepContext{}
+	tcx.Init([]*Point{NewPoint64(0, 0), NewPoint64(10, 0), NewPoint64(0, 10), NewPoint64(10, 10)})
+	err := tcx.TriangulateE()
+	e, ok := err.(*IntersectingConstraintsError)
+	if !ok {
+		t.Fatalf("got %T %v, want *IntersectingConstraintsError", err, err)
+	}
+	if len(e.Points) != len(e.Indices) {
+		t.Fatalf("got %d points and %d indices", len(e.Points), len(e.Indices))
+	}
+	for i := 0; i < len(e.Points); i++ {
+		if e.Indices[i] < 0 || tcx.InputIndex(e.Points[i]) != e.Indices[i] {
+			t.Errorf("point %v has index %d", e.Points[i], e.Indices[i])
+		}
+	}
+}
+
+func TestTriangulatePanics(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(*DuplicatePointError); !ok {
+			t.Error("Triangulate did not panic with the error")
+		}
+	}()
+	tcx := &SweepContext{}
+	tcx.Init([]*Point{NewPoint64(0, 0), NewPoint64(10, 0), NewPoint64(10, 0), NewPoint64(0, 10)})
+	tcx.Triangulate()
+}
+
+func TestRuntimeErrorsAreRaisedAgain(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("the runtime error was recovered")
+		}
+	}()
+	tcx := &SweepContext{}
+	var list []int
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				tcx.recoverError(r)
+			}
+		}()
+		_ = list[1]
+	}()
+}
@@ -0,0 +1,234 @@
+package poly2tri
+
+import (
+	"sort"
+	"strconv"
+)
+
+// FillRule tells which winding numbers are inside the rings, like in vector graphics. A
+// counterclockwise ring adds one to the winding number of the points it encloses and a
+// clockwise ring removes one, y going up.
+type FillRule int
+
+const (
+	// FillEvenOdd fills the points inside an odd number of rings, the rule of Init and AddHole.
+	FillEvenOdd FillRule = iota
+	// FillNonZero fills the points with a winding number other than 0.
+	FillNonZero
+	// FillPositive fills the points with a positive winding number.
+	FillPositive
+	// FillNegative fills the points with a negative winding number.
+	FillNegative
+)
+
+var fillRuleNames = []string{
+	"even-odd",
+	"non-zero",
+	"positive",
+	"negative",
+}
+
+func (this FillRule) String() string {
+	if int(this) < len(fillRuleNames) {
+		return fillRuleNames[this]
+	}
+	return "FillRule(" + strconv.Itoa(int(this)) + ")"
+}
+
+// contains tells if the winding number is inside.
+func (this FillRule) contains(winding int) bool {
+	switch this {
+	case FillNonZero:
+		return winding != 0
+	case FillPositive:
+		return winding > 0
+	case FillNegative:
+		return winding < 0
+	}
+	return winding%2 != 0
+}
+
+// InitRings starts a new triangulation of closed rings filled with rule, without telling
+// contours from holes. The rings may cross or touch each other and themselves: points
+// with the same coordinates are merged and the edges are split where they cross, the
+// crossings becoming points created by the library.
+func (this *SweepContext) InitRings(rings [][]*Point, rule FillRule) {
+	this.Init(nil)
+	this.fill_rule = rule
+	this.rings = [][]*Point{}
+	for i := 0; i < len(rings); i++ {
+		this.addIndices(rings[i])
+	}
+	split := this.predicates.splitRings(rings)
+	seen := make(map[*Point]bool)
+	edges := make(map[[2]*Point]bool)
+	for i := 0; i < len(split); i++ {
+		ring := split[i]
+		this.rings = append(this.rings, ring)
+		for j := 0; j < len(ring); j++ {
+			p, q := ring[j], ring[(j+1)%len(ring)]
+			if !seen[p] {
+				seen[p] = true
+				this.points = append(this.points, p)
+			}
+			if edges[[2]*Point{p, q}] || edges[[2]*Point{q, p}] {
+				continue
+			}
+			edges[[2]*Point{p, q}] = true
+			edge := NewEdge(p, q)
+			this.edge_list = append(this.edge_list, edge)
+			this.point_edges[edge.q] = append(this.point_edges[edge.q], edge)
+		}
+	}
+}
+
+// ringSegment is an edge a-b of the rings with the points splitting it.
+type ringSegment struct {
+	a, b       *Point
+	xmin, xmax float64
+	splits     []*Point
+}
+
+// splitRings returns the rings with one point for each place and with the points where
+// their edges cross or touch inserted. The rings without area become empty.
+func (this Predicates) splitRings(rings [][]*Point) [][]*Point {
+	places := make(map[[2]float64]*Point)
+	place := func(p *Point) *Point {
+		key := [2]float64{p.x, p.y}
+		if q, ok := places[key]; ok {
+			return q
+		}
+		places[key] = p
+		return p
+	}
+	merged := [][]*Point{}
+	for i := 0; i < len(rings); i++ {
+		ring := []*Point{}
+		for j := 0; j < len(rings[i]); j++ {
+			if p := place(rings[i][j]); len(ring) == 0 || ring[len(ring)-1] != p {
+				ring = append(ring, p)
+			}
+		}
+		for len(ring) > 1 && ring[len(ring)-1] == ring[0] {
+			ring = ring[:len(ring)-1]
+		}
+		if !this.ringHasArea(ring) {
+			ring = []*Point{}
+		}
+		merged = append(merged, ring)
+	}
+	// The edges overlapping each other are split first, so that they become the same
+	// edges before their crossings are rounded. The rounded crossings may then make new
+	// ones with the edges passing close by.
+	for changed := true; changed; {
+		merged, changed = this.splitCrossings(merged, nil)
+	}
+	for changed := true; changed; {
+		merged, changed = this.splitCrossings(merged, place)
+	}
+	return merged
+}
+
+// splitCrossings inserts in the rings the points of their edges lying on other edges,
+// and the points where they cross when place is given to make them. It tells if there
+// were any.
+func (this Predicates) splitCrossings(rings [][]*Point, place func(*Point) *Point) ([][]*Point, bool) {
+	// One segment for the edges with the same ends, so that they are split the same way
+	segments := make(map[[2]*Point]*ringSegment)
+	all := []*ringSegment{}
+	for i := 0; i < len(rings); i++ {
+		ring := rings[i]
+		for j := 0; j < len(ring); j++ {
+			a, b := ring[j], ring[(j+1)%len(ring)]
+			if segments[[2]*Point{a, b}] == nil && segments[[2]*Point{b, a}] == nil {
+				s := &ringSegment{a: a, b: b, xmin: minFloat(a.x, b.x), xmax: maxFloat(a.x, b.x)}
+				segments[[2]*Point{a, b}] = s
+				all = append(all, s)
+			}
+		}
+	}
+	// Pairs of segments whose x ranges overlap
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].xmin < all[j].xmin
+	})
+	changed := false
+	for i := 0; i < len(all); i++ {
+		s1 := all[i]
+		for j := i + 1; j < len(all) && all[j].xmin <= s1.xmax; j++ {
+			s2 := all[j]
+			a, b, c, d := s1.a, s1.b, s2.a, s2.b
+			if maxFloat(a.y, b.y) < minFloat(c.y, d.y) || maxFloat(c.y, d.y) < minFloat(a.y, b.y) {
+				continue
+			}
+			o1, o2 := this.orientation(a, b, c), this.orientation(a, b, d)
+			o3, o4 := this.orientation(c, d, a), this.orientation(c, d, b)
+			if o1*o2 < 0 && o3*o4 < 0 {
+				if place != nil {
+					ab, cd := product(a, b, c), product(a, b, d)
+					t := ab / (ab - cd)
+					p := NewPoint64(c.x+(d.x-c.x)*t, c.y+(d.y-c.y)*t)
+					// The values are the mean of the ones along both segments
+					u := product(c, d, a) / (product(c, d, a) - product(c, d, b))
+					p.interpolate([]*Point{a, b, c, d}, []float64{(1 - u) / 2, u / 2, (1 - t) / 2, t / 2})
+					p = place(p)
+					s1.splits = append(s1.splits, p)
+					s2.splits = append(s2.splits, p)
+					changed = true
+				}
+				continue
+			}
+			// A point of one segment inside the other one
+			if o1 == 0 && c != a && c != b && onSegment(a, b, c) {
+				s1.splits = append(s1.splits, c)
+				changed = true
+			}
+			if o2 == 0 && d != a && d != b && onSegment(a, b, d) {
+				s1.splits = append(s1.splits, d)
+				changed = true
+			}
+			if o3 == 0 && a != c && a != d && onSegment(c, d, a) {
+				s2.splits = append(s2.splits, a)
+				changed = true
+			}
+			if o4 == 0 && b != c && b != d && onSegment(c, d, b) {
+				s2.splits = append(s2.splits, b)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return rings, false
+	}
+	for i := 0; i < len(all); i++ {
+		s := all[i]
+		dx, dy := s.b.x-s.a.x, s.b.y-s.a.y
+		sort.Slice(s.splits, func(k, l int) bool {
+			pk, pl := s.splits[k], s.splits[l]
+			return (pk.x-s.a.x)*dx+(pk.y-s.a.y)*dy < (pl.x-s.a.x)*dx+(pl.y-s.a.y)*dy
+		})
+	}
+	result := [][]*Point{}
+	for i := 0; i < len(rings); i++ {
+		ring := []*Point{}
+		for j := 0; j < len(rings[i]); j++ {
+			a, b := rings[i][j], rings[i][(j+1)%len(rings[i])]
+			ring = append(ring, a)
+			if s := segments[[2]*Point{a, b}]; s != nil {
+				for k := 0; k < len(s.splits); k++ {
+					if p := s.splits[k]; p != ring[len(ring)-1] && p != b {
+						ring = append(ring, p)
+					}
+				}
+			} else {
+				s = segments[[2]*Point{b, a}]
+				for k := len(s.splits) - 1; k >= 0; k-- {
+					if p := s.splits[k]; p != ring[len(ring)-1] && p != b {
+						ring = append(ring, p)
+					}
+				}
+			}
+		}
+		result = append(result, ring)
+	}
+	return result, true
+}
@@ -0,0 +1,84 @@
+package poly2tri
+
+// IndexedMesh holds triangles as a vertex buffer and an index buffer, each point once,
+// ready to be uploaded to the GPU. The triangles are counterclockwise with y going up.
+type IndexedMesh struct {
+	// Points holds the points of the triangles in the order they first appear.
+	Points []*Point
+	// Vertices holds x and y for each point of Points.
+	Vertices []float32
+	// Indices holds the indices in Points of the three points of each triangle.
+	Indices []uint32
+	// Adjacency holds six indices for each triangle when asked for, laid out like
+	// GL_TRIANGLES_ADJACENCY: each point of the triangle followed by the point opposite to
+	// the next side in the neighbor across it, or by the third point of the triangle when
+	// there is no neighbor among the triangles.
+	Adjacency []uint32
+	triangles []*Triangle
+	ids       map[*Point]uint32
+}
+
+// ToIndexedMesh builds the indexed mesh of triangles, with adjacency if asked for.
+func ToIndexedMesh(triangles []*Triangle, adjacency bool) *IndexedMesh {
+	mesh := &IndexedMesh{Points: []*Point{}, Vertices: []float32{}, Indices: make([]uint32, 0, 3*len(triangles))}
+	mesh.triangles = triangles
+	ids := make(map[*Point]uint32)
+	mesh.ids = ids
+	for i := 0; i < len(triangles); i++ {
+		ps := triangles[i].points
+		for j := 0; j < 3; j++ {
+			id, ok := ids[ps[j]]
+			if !ok {
+				id = uint32(len(mesh.Points))
+				ids[ps[j]] = id
+				mesh.Points = append(mesh.Points, ps[j])
+				mesh.Vertices = append(mesh.Vertices, float32(ps[j].x), float32(ps[j].y))
+			}
+			mesh.Indices = append(mesh.Indices, id)
+		}
+	}
+	if !adjacency {
+		return mesh
+	}
+	in := make(map[*Triangle]bool)
+	for i := 0; i < len(triangles); i++ {
+		in[triangles[i]] = true
+	}
+	mesh.Adjacency = make([]uint32, 0, 6*len(triangles))
+	for i := 0; i < len(triangles); i++ {
+		t := triangles[i]
+		for j := 0; j < 3; j++ {
+			// The side from points[j] to points[j+1] is across points[j+2]
+			k := (j + 2) % 3
+			opposite := t.points[k]
+			if n := t.neighbors[k]; n != nil && in[n] {
+				opposite = n.oppositePoint(t, t.points[k])
+			}
+			mesh.Adjacency = append(mesh.Adjacency, ids[t.points[j]], ids[opposite])
+		}
+	}
+	return mesh
+}
+
+// Indices16 returns the indices as uint16, or false when there are too many points for them.
+func (this *IndexedMesh) Indices16() ([]uint16, bool) {
+	return shortIndices(this.Indices, len(this.Points), 1<<16)
+}
+
+// Adjacency16 returns the adjacency indices as uint16, or false when there are too many
+// points for them.
+func (this *IndexedMesh) Adjacency16() ([]uint16, bool) {
+	return shortIndices(this.Adjacency, len(this.Points), 1<<16)
+}
+
+// shortIndices converts indices to uint16 if there are at most limit points.
+func shortIndices(indices []uint32, points, limit int) ([]uint16, bool) {
+	if points > limit {
+		return nil, false
+	}
+	result := make([]uint16, len(indices))
+	for i := 0; i < len(indices); i++ {
+		result[i] = uint16(indices[i])
+	}
+	return result, true
+}
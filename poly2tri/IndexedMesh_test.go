@@ -0,0 +1,137 @@
+package poly2tri
+
+import "testing"
+
+// band returns n neighboring triangles in a row along the x axis, on n+2 points.
+func band(n int) []*Triangle {
+	points := make([]*Point, n+2)
+	for i := 0; i < len(points); i++ {
+		points[i] = NewPoint64(float64(i/2), float64(i%2))
+	}
+	triangles := make([]*Triangle, n)
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			triangles[i] = NewTriangle(points[i], points[i+2], points[i+1])
+		} else {
+			triangles[i] = NewTriangle(points[i], points[i+1], points[i+2])
+		}
+		if i > 0 {
+			triangles[i].markNeighbor(triangles[i-1])
+		}
+	}
+	return triangles
+}
+
+func TestIndexedMesh(t *testing.T) {
+	contour, hole, points := insertionPolygon(5)
+	triangles := triangulated(t, contour, hole, points).GetTriangles()
+	mesh := ToIndexedMesh(triangles, true)
+	if len(mesh.Indices) != 3*len(triangles) || len(mesh.Adjacency) != 6*len(triangles) {
+		t.Fatalf("got %d indices and %d adjacency indices for %d triangles", len(mesh.Indices), len(mesh.Adjacency), len(triangles))
+	}
+	if len(mesh.Vertices) != 2*len(mesh.Points) {
+		t.Fatalf("got %d values for %d points", len(mesh.Vertices), len(mesh.Points))
+	}
+	// The points come in the order they first appear, and the indices keep the winding
+	seen := make(map[*Point]bool)
+	for i := 0; i < len(triangles); i++ {
+		for j := 0; j < 3; j++ {
+			p := triangles[i].points[j]
+			if !seen[p] {
+				if len(seen) >= len(mesh.Points) || mesh.Points[len(seen)] != p {
+					t.Fatalf("point %v is not at index %d", p, len(seen))
+				}
+				seen[p] = true
+			}
+			if mesh.Points[mesh.Indices[3*i+j]] != p {
+				t.Fatalf("index %d of triangle %d is not %v", j, i, p)
+			}
+		}
+	}
+	if len(seen) != len(mesh.Points) {
+		t.Fatalf("got %d points, want %d", len(mesh.Points), len(seen))
+	}
+	for i := 0; i < len(mesh.Points); i++ {
+		if mesh.Vertices[2*i] != float32(mesh.Points[i].x) || mesh.Vertices[2*i+1] != float32(mesh.Points[i].y) {
+			t.Fatalf("the values of point %d are not %v", i, mesh.Points[i])
+		}
+	}
+	for i := 0; i < len(triangles); i++ {
+		a, b, c := mesh.Indices[3*i], mesh.Indices[3*i+1], mesh.Indices[3*i+2]
+		ax, ay := mesh.Vertices[2*a], mesh.Vertices[2*a+1]
+		bx, by := mesh.Vertices[2*b], mesh.Vertices[2*b+1]
+		cx, cy := mesh.Vertices[2*c], mesh.Vertices[2*c+1]
+		if (bx-ax)*(cy-ay)-(by-ay)*(cx-ax) <= 0 {
+			t.Errorf("triangle %d is not counterclockwise", i)
+		}
+	}
+	// Each side is followed by the far point of the neighbor across it, or by the third
+	// point of the triangle on the boundary
+	in := make(map[*Triangle]bool)
+	for i := 0; i < len(triangles); i++ {
+		in[triangles[i]] = true
+	}
+	boundary := 0
+	for i := 0; i < len(triangles); i++ {
+		tr := triangles[i]
+		for j := 0; j < 3; j++ {
+			if mesh.Adjacency[6*i+2*j] != mesh.Indices[3*i+j] {
+				t.Fatalf("adjacency %d of triangle %d is not its point", 2*j, i)
+			}
+			k := (j + 2) % 3
+			got := mesh.Points[mesh.Adjacency[6*i+2*j+1]]
+			n := tr.neighbors[k]
+			if n == nil || !in[n] {
+				boundary++
+				if got != tr.points[k] {
+					t.Errorf("boundary side %d of triangle %d is followed by %v, want %v", j, i, got, tr.points[k])
+				}
+				continue
+			}
+			if got == tr.points[j] || got == tr.points[(j+1)%3] || got == tr.points[k] ||
+				!n.containsPoints(tr.points[j], tr.points[(j+1)%3]) || !n.containsPoint(got) {
+				t.Errorf("side %d of triangle %d is followed by %v, not the far point of %v", j, i, got, n.points)
+			}
+		}
+	}
+	// The contour and the hole
+	if boundary != len(contour)+len(hole) {
+		t.Errorf("got %d boundary sides, want %d", boundary, len(contour)+len(hole))
+	}
+	short, ok := mesh.Indices16()
+	if !ok || len(short) != len(mesh.Indices) {
+		t.Fatalf("got %d short indices and %v", len(short), ok)
+	}
+	for i := 0; i < len(short); i++ {
+		if uint32(short[i]) != mesh.Indices[i] {
+			t.Fatalf("short index %d is %d, want %d", i, short[i], mesh.Indices[i])
+		}
+	}
+	adjacency, ok := mesh.Adjacency16()
+	if !ok || len(adjacency) != len(mesh.Adjacency) {
+		t.Fatalf("got %d short adjacency indices and %v", len(adjacency), ok)
+	}
+	for i := 0; i < len(adjacency); i++ {
+		if uint32(adjacency[i]) != mesh.Adjacency[i] {
+			t.Fatalf("short adjacency index %d is %d, want %d", i, adjacency[i], mesh.Adjacency[i])
+		}
+	}
+}
+
+func TestIndexedMeshOverflow(t *testing.T) {
+	mesh := ToIndexedMesh(band(1<<16-2), true)
+	short, ok := mesh.Indices16()
+	if !ok || short[len(short)-1] != 1<<16-1 {
+		t.Errorf("the indices of %d points do not fit in 16 bits", len(mesh.Points))
+	}
+	if _, ok := mesh.Adjacency16(); !ok {
+		t.Errorf("the adjacency of %d points does not fit in 16 bits", len(mesh.Points))
+	}
+	mesh = ToIndexedMesh(band(1<<16-1), true)
+	if short, ok := mesh.Indices16(); ok || short != nil {
+		t.Errorf("the indices of %d points fit in 16 bits", len(mesh.Points))
+	}
+	if short, ok := mesh.Adjacency16(); ok || short != nil {
+		t.Errorf("the adjacency of %d points fits in 16 bits", len(mesh.Points))
+	}
+}
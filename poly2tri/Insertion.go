@@ -0,0 +1,332 @@
+package poly2tri
+
+// fanSide is a side x-y of the polygon around a point being inserted, with what lies beyond it.
+type fanSide struct {
+	x           *Point
+	y           *Point
+	neighbor    *Triangle
+	constrained bool
+	interior    bool
+	region      int
+	attribute   int
+}
+
+// sidesOf returns the sides of t but the one across t.points[i], in counterclockwise order.
+func sidesOf(t *Triangle, i int) []*fanSide {
+	sides := []*fanSide{}
+	for k := 1; k < 3; k++ {
+		j := (i + k) % 3
+		sides = append(sides, &fanSide{t.points[(j+1)%3], t.points[(j+2)%3], t.neighbors[j], t.constrained_edge[j], t.interior, t.region, t.attribute})
+	}
+	return sides
+}
+
+// fillFan triangulates the star around p with one triangle x,y,p per side. The triangles
+// in reuse are recycled first so they never have to be dropped from maps.
+func (this *SweepContext) fillFan(p *Point, sides []*fanSide, reuse []*Triangle) []*Triangle {
+	fan := make([]*Triangle, len(sides))
+	for i := 0; i < len(sides); i++ {
+		s := sides[i]
+		var t *Triangle
+		if i < len(reuse) {
+			t = reuse[i]
+			t.Init(s.x, s.y, p)
+		} else {
+			t = NewTriangle(s.x, s.y, p)
+			this.maps = append(this.maps, t)
+		}
+		t.interior = s.interior
+		t.region = s.region
+		t.attribute = s.attribute
+		t.constrained_edge[2] = s.constrained
+		fan[i] = t
+	}
+	for i := 0; i < len(fan); i++ {
+		if sides[i].neighbor != nil {
+			fan[i].markNeighbor(sides[i].neighbor)
+		}
+		for j := i + 1; j < len(fan); j++ {
+			fan[i].markNeighbor(fan[j])
+		}
+	}
+	return fan
+}
+
+// splitTriangle inserts p strictly inside t.
+func (this *SweepContext) splitTriangle(t *Triangle, p *Point) []*Triangle {
+	sides := []*fanSide{}
+	for i := 0; i < 3; i++ {
+		sides = append(sides, &fanSide{t.points[(i+1)%3], t.points[(i+2)%3], t.neighbors[i], t.constrained_edge[i], t.interior, t.region, t.attribute})
+	}
+	return this.fillFan(p, sides, []*Triangle{t})
+}
+
+// splitEdge inserts p on the side of t across t.points[i], splitting the triangles on
+// both sides. A constrained side stays constrained on both halves.
+func (this *SweepContext) splitEdge(t *Triangle, i int, p *Point) []*Triangle {
+	x, y := t.points[(i+1)%3], t.points[(i+2)%3]
+	constrained := t.constrained_edge[i]
+	sides := sidesOf(t, i)
+	reuse := []*Triangle{t}
+	if ot := t.neighbors[i]; ot != nil {
+		sides = append(sides, sidesOf(ot, ot.edgeIndex(x, y))...)
+		reuse = append(reuse, ot)
+	}
+	fan := this.fillFan(p, sides, reuse)
+	if constrained {
+		for j := 0; j < len(fan); j++ {
+			fan[j].markConstrainedEdgeByPoints(p, x)
+			fan[j].markConstrainedEdgeByPoints(p, y)
+		}
+		label := this.side_labels[[2]*Point{x, y}]
+		this.setSideLabel(x, y, 0)
+		this.setSideLabel(p, x, label)
+		this.setSideLabel(p, y, label)
+	}
+	return fan
+}
+
+// insertIn adds p to the mesh, t being a triangle that contains it, and restores the
+// Delaunay property around it. It returns the triangles around p, or nil when p is
+// already a point of t.
+func (this *SweepContext) insertIn(t *Triangle, p *Point) []*Triangle {
+	var fan []*Triangle
+	for i := 0; i < 3 && fan == nil; i++ {
+		if p.equals(t.points[i]) {
+			return nil
+		}
+		if this.predicates.orientation(t.points[(i+1)%3], t.points[(i+2)%3], p) == 0 {
+			fan = this.splitEdge(t, i, p)
+		}
+	}
+	if fan == nil {
+		fan = this.splitTriangle(t, p)
+	}
+	return this.legalizeFan(p, fan)
+}
+
+// legalizeFan flips the sides across p until the star of p is Delaunay again and returns
+// the triangles around p. Only sides between two interior triangles are flipped.
+func (this *SweepContext) legalizeFan(p *Point, fan []*Triangle) []*Triangle {
+	stack := append([]*Triangle{}, fan...)
+	star := append([]*Triangle{}, fan...)
+	for len(stack) > 0 {
+		t := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		i := t.index(p)
+		ot := t.neighbors[i]
+		if ot == nil || t.constrained_edge[i] || !t.interior || !ot.interior {
+			continue
+		}
+		op := ot.oppositePoint(t, p)
+		if this.predicates.inCircle(p, t.pointCCW(p), t.pointCW(p), op) {
+			this.sweep.rotateTrianglePair(t, p, ot, op)
+			stack = append(stack, t, ot)
+			star = append(star, ot)
+		}
+	}
+	seen := make(map[*Triangle]bool)
+	result := []*Triangle{}
+	for i := 0; i < len(star); i++ {
+		if !seen[star[i]] && star[i].containsPoint(p) {
+			seen[star[i]] = true
+			result = append(result, star[i])
+		}
+	}
+	return result
+}
+
+// legalizeAll flips the interior sides that fail the Delaunay test, like the few the sweep leaves behind.
+func (this *SweepContext) legalizeAll() {
+	this.legalizeTriangles(this.triangles)
+}
+
+// legalizeTriangles flips the sides of triangles that fail the Delaunay test, then the
+// sides of the triangles changed by the flips. It returns the triangles it flipped.
+func (this *SweepContext) legalizeTriangles(triangles []*Triangle) []*Triangle {
+	stack := append([]*Triangle{}, triangles...)
+	flipped := []*Triangle{}
+	for len(stack) > 0 {
+		t := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for i := 0; i < 3; i++ {
+			ot := t.neighbors[i]
+			if ot == nil || t.constrained_edge[i] || !t.interior || !ot.interior {
+				continue
+			}
+			p := t.points[i]
+			op := ot.oppositePoint(t, p)
+			if this.predicates.inCircle(p, t.pointCCW(p), t.pointCW(p), op) {
+				this.sweep.rotateTrianglePair(t, p, ot, op)
+				stack = append(stack, t, ot)
+				flipped = append(flipped, t, ot)
+				break
+			}
+		}
+	}
+	return flipped
+}
+
+// walk follows the segment from the centroid of t towards p. It returns the triangle
+// containing p and -1, or the triangle and index of the first outer side on the way, or
+// of the first constrained one unless crossSegments. It returns nil when the walk gets
+// lost on degenerate geometry.
+func (this *SweepContext) walk(t *Triangle, p *Point, crossSegments bool) (*Triangle, int) {
+	g := NewPoint64((t.points[0].x+t.points[1].x+t.points[2].x)/3, (t.points[0].y+t.points[1].y+t.points[2].y)/3)
+	for steps := 0; steps <= len(this.maps); steps++ {
+		next := -1
+		inside := true
+		for i := 0; i < 3; i++ {
+			a, b := t.points[(i+1)%3], t.points[(i+2)%3]
+			if this.predicates.orientation(a, b, p) < 0 {
+				inside = false
+				if this.predicates.orientation(g, p, a) <= 0 && this.predicates.orientation(g, p, b) >= 0 {
+					next = i
+					break
+				}
+			}
+		}
+		if inside {
+			return t, -1
+		}
+		if next < 0 {
+			return nil, -1
+		}
+		if (t.constrained_edge[next] && !crossSegments) || t.neighbors[next] == nil {
+			return t, next
+		}
+		t = t.neighbors[next]
+	}
+	return nil, -1
+}
+
+// locate returns a triangle of the mesh containing p, or nil.
+func (this *SweepContext) locate(p *Point) *Triangle {
+	for i := 0; i < len(this.maps); i++ {
+		if this.maps[i].pointInsideTriangle(p, this.predicates) {
+			return this.maps[i]
+		}
+	}
+	return nil
+}
+
+// collectTriangles lists the interior triangles again after the mesh was edited and
+// updates the attached AStar graphs.
+func (this *SweepContext) collectTriangles() {
+	triangles := []*Triangle{}
+	for i := 0; i < len(this.maps); i++ {
+		if this.maps[i].interior {
+			triangles = append(triangles, this.maps[i])
+		}
+	}
+	this.triangles = triangles
+	this.applyLabels(this.maps)
+	for i := 0; i < len(this.astars); i++ {
+		this.astars[i].update(triangles)
+	}
+}
+
+// updateTriangles is collectTriangles after an edit that changed only the triangles of
+// touched, new ones included: only they get their labels again, and the attached AStar
+// graphs are patched around them. On an error the edit may have stopped halfway, so the
+// whole mesh is collected again.
+func (this *SweepContext) updateTriangles(touched []*Triangle, err error) {
+	if err != nil {
+		this.collectTriangles()
+		return
+	}
+	triangles := []*Triangle{}
+	for i := 0; i < len(this.maps); i++ {
+		if this.maps[i].interior {
+			triangles = append(triangles, this.maps[i])
+		}
+	}
+	this.triangles = triangles
+	this.applyLabels(touched)
+	for i := 0; i < len(this.astars); i++ {
+		this.astars[i].patch(touched)
+	}
+}
+
+// InsertPoint adds p to the triangulation as a new vertex and flips edges around it until
+// the mesh is Delaunay again, leaving constrained edges in place. A point on a constrained
+// edge splits it in two constrained edges. Triangulate has to succeed first; p is then
+// part of the input, so triangulating again keeps it.
+//
+// The error is an *OutsidePointError when p is not inside the triangulated area, or a
+// *DuplicatePointError when it is already a vertex of the mesh.
+func (this *SweepContext) InsertPoint(p *Point) (err error) {
+	if len(this.triangles) == 0 {
+		return &InternalError{Op: "SweepContext.InsertPoint() (not triangulated)"}
+	}
+	var star []*Triangle
+	defer func() {
+		if r := recover(); r != nil {
+			err = this.recoverError(r)
+		}
+		this.updateTriangles(star, err)
+	}()
+	star, err = this.insertPoint(p)
+	return err
+}
+
+// insertPoint inserts p and returns the triangles around it, the only ones it changed.
+func (this *SweepContext) insertPoint(p *Point) ([]*Triangle, error) {
+	t := this.locateInterior(p)
+	if t == nil {
+		return nil, &OutsidePointError{Points: []*Point{p}, Indices: this.indicesOf([]*Point{p})}
+	}
+	for i := 0; i < 3; i++ {
+		if p.equals(t.points[i]) {
+			return nil, &DuplicatePointError{Points: []*Point{t.points[i], p}, Indices: this.indicesOf([]*Point{t.points[i], p})}
+		}
+	}
+	if !t.interior {
+		return nil, &OutsidePointError{Points: []*Point{p}, Indices: this.indicesOf([]*Point{p})}
+	}
+	star := this.insertIn(t, p)
+	this.AddPoint(p)
+	return star, nil
+}
+
+// locateInterior returns a triangle containing p, an interior one when p is on the border
+// of the triangulated area, or nil when p is outside the mesh.
+func (this *SweepContext) locateInterior(p *Point) *Triangle {
+	t, i := this.walk(this.triangles[0], p, true)
+	if t == nil || i >= 0 {
+		// The walk got lost, or left the mesh through a dent of its border
+		t = this.locate(p)
+	}
+	if t == nil || t.interior {
+		return t
+	}
+	for i := 0; i < 3; i++ {
+		if p.equals(t.points[i]) {
+			return t
+		}
+		n := t.neighbors[i]
+		if n != nil && n.interior && this.predicates.orientation(t.points[(i+1)%3], t.points[(i+2)%3], p) == 0 {
+			return n
+		}
+	}
+	return t
+}
+
+// vertexTriangle returns a triangle with the vertex v, walking from hint, a triangle
+// near v, when hint is not one already. It returns nil when v is not a vertex of the mesh.
+func (this *SweepContext) vertexTriangle(v *Point, hint *Triangle) *Triangle {
+	if hint != nil && hint.containsPoint(v) {
+		return hint
+	}
+	if hint == nil {
+		hint = this.triangles[0]
+	}
+	t, i := this.walk(hint, v, true)
+	if t == nil || i >= 0 {
+		t = this.locate(v)
+	}
+	if t == nil || !t.containsPoint(v) {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,101 @@
+package poly2tri
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// meshKeys returns the triangles as sorted strings of their coordinates, so that two
+// meshes of the same points can be compared.
+func meshKeys(triangles []*Triangle) []string {
+	keys := []string{}
+	for i := 0; i < len(triangles); i++ {
+		corners := []string{}
+		for j := 0; j < 3; j++ {
+			corners = append(corners, triangles[i].points[j].toString())
+		}
+		sort.Strings(corners)
+		keys = append(keys, strings.Join(corners, " "))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sameMesh(t *testing.T, got, want []*Triangle) {
+	t.Helper()
+	g, w := meshKeys(got), meshKeys(want)
+	if len(g) != len(w) {
+		t.Fatalf("got %d triangles, want %d", len(g), len(w))
+	}
+	for i := 0; i < len(g); i++ {
+		if g[i] != w[i] {
+			t.Fatalf("got triangle %s, want %s", g[i], w[i])
+		}
+	}
+}
+
+// insertionPolygon returns an irregular contour and hole, and random points inside.
+func insertionPolygon(seed int64) ([]*Point, []*Point, []*Point) {
+	p := NewPoint64
+	contour := []*Point{p(0, 0), p(47, 3), p(103, -2), p(98, 51), p(55, 44), p(49, 97), p(2, 93)}
+	hole := []*Point{p(21, 21), p(24, 37), p(36, 33), p(33, 19)}
+	r := rand.New(rand.NewSource(seed))
+	points := []*Point{}
+	for len(points) < 60 {
+		q := p(r.Float64()*100, r.Float64()*95)
+		if RobustPredicates.pointInRing(q, contour) > 0 && RobustPredicates.pointInRing(q, hole) < 0 {
+			points = append(points, q)
+		}
+	}
+	return contour, hole, points
+}
+
+// triangulated returns the constrained Delaunay triangulation of the polygon. The sweep
+// may leave a few sides failing the Delaunay test, they are flipped.
+func triangulated(t *testing.T, contour, hole, points []*Point) *SweepContext {
+	tcx := &SweepContext{}
+	tcx.Init(contour)
+	tcx.AddHole(hole)
+	tcx.AddPoints(points)
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	tcx.legalizeAll()
+	return tcx
+}
+
+func TestInsertPoint(t *testing.T) {
+	contour, hole, points := insertionPolygon(1)
+	tcx := triangulated(t, contour, hole, points[:20])
+	for i := 20; i < len(points); i++ {
+		if err := tcx.InsertPoint(points[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkMesh(t, tcx.GetTriangles())
+	sameMesh(t, tcx.GetTriangles(), triangulated(t, contour, hole, points).GetTriangles())
+	// On a constrained edge
+	edge := NewPoint64(75, 0.5)
+	if err := tcx.InsertPoint(edge); err != nil {
+		t.Fatal(err)
+	}
+	split := append([]*Point{contour[0], contour[1], edge}, contour[2:]...)
+	sameMesh(t, tcx.GetTriangles(), triangulated(t, split, hole, points).GetTriangles())
+}
+
+func TestInsertPointErrors(t *testing.T) {
+	contour, hole, points := insertionPolygon(1)
+	tcx := triangulated(t, contour, hole, points)
+	if err := tcx.InsertPoint(NewPoint64(30, 30)); err == nil {
+		t.Error("a point in the hole was inserted")
+	} else if _, ok := err.(*OutsidePointError); !ok {
+		t.Errorf("got %T %v, want *OutsidePointError", err, err)
+	}
+	if err := tcx.InsertPoint(NewPoint64(points[3].x, points[3].y)); err == nil {
+		t.Error("a point already in the mesh was inserted")
+	} else if _, ok := err.(*DuplicatePointError); !ok {
+		t.Errorf("got %T %v, want *DuplicatePointError", err, err)
+	}
+}
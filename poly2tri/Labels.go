@@ -0,0 +1,107 @@
+package poly2tri
+
+import "math"
+
+// edgeLabel is the label of the input edge p-q.
+type edgeLabel struct {
+	p, q  *Point
+	label int
+}
+
+// SetEdgeLabel labels the input edge p-q, like a wall, a piece of coastline or a boundary
+// condition. The sides of the triangles along the edge get the label, also when collinear
+// points, crossings or refinement split the edge. 0 is no label, and a later label of
+// the same edge wins.
+func (this *SweepContext) SetEdgeLabel(p, q *Point, label int) {
+	this.edge_labels = append(this.edge_labels, &edgeLabel{p, q, label})
+}
+
+// labelSides finds the sides of the mesh along the labelled input edges.
+func (this *SweepContext) labelSides() {
+	this.side_labels = make(map[[2]*Point]int)
+	if len(this.edge_labels) == 0 {
+		return
+	}
+	around := make(map[*Point]*Triangle)
+	places := make(map[[2]float64]*Point)
+	for i := 0; i < len(this.maps); i++ {
+		for j := 0; j < 3; j++ {
+			p := this.maps[i].points[j]
+			around[p] = this.maps[i]
+			places[[2]float64{p.x, p.y}] = p
+		}
+	}
+	for i := 0; i < len(this.edge_labels); i++ {
+		// The input points may have been merged with others at the same place
+		p := places[[2]float64{this.edge_labels[i].p.x, this.edge_labels[i].p.y}]
+		q := places[[2]float64{this.edge_labels[i].q.x, this.edge_labels[i].q.y}]
+		if p == nil || q == nil || p == q {
+			continue
+		}
+		chain := labelChain(p, q, around)
+		for j := 1; j < len(chain); j++ {
+			this.setSideLabel(chain[j-1], chain[j], this.edge_labels[i].label)
+		}
+	}
+}
+
+// labelChain returns the points of the constrained sides of the mesh along p-q, from p to
+// q, or nil if they do not reach q. The points may be off p-q by rounding, like the
+// crossings of InitRings.
+func labelChain(p, q *Point, around map[*Point]*Triangle) []*Point {
+	length := (q.x-p.x)*(q.x-p.x) + (q.y-p.y)*(q.y-p.y)
+	chain := []*Point{p}
+	for a := p; a != q; {
+		var next *Point
+		star := pointStar(around[a], a)
+		for i := 0; i < len(star) && next == nil; i++ {
+			t := star[i]
+			for k := 0; k < 2; k++ {
+				x := t.pointCCW(a)
+				if k == 1 {
+					x = t.pointCW(a)
+				}
+				if !t.constrained_edge[t.edgeIndex(a, x)] {
+					continue
+				}
+				if x == q || (math.Abs(product(p, x, q)) <= 1e-9*length && encroaches(p, q, x) &&
+					(x.x-a.x)*(q.x-a.x)+(x.y-a.y)*(q.y-a.y) > 0) {
+					next = x
+					break
+				}
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		a = next
+		chain = append(chain, a)
+	}
+	return chain
+}
+
+func (this *SweepContext) setSideLabel(p, q *Point, label int) {
+	if label == 0 {
+		delete(this.side_labels, [2]*Point{p, q})
+		delete(this.side_labels, [2]*Point{q, p})
+		return
+	}
+	this.side_labels[[2]*Point{p, q}] = label
+	this.side_labels[[2]*Point{q, p}] = label
+}
+
+// applyLabels gives the triangles the labels of their sides.
+func (this *SweepContext) applyLabels(triangles []*Triangle) {
+	for i := 0; i < len(triangles); i++ {
+		t := triangles[i]
+		for j := 0; j < 3; j++ {
+			label := this.side_labels[[2]*Point{t.points[(j+1)%3], t.points[(j+2)%3]}]
+			if label != 0 && t.labels == nil {
+				t.labels = []int{0, 0, 0}
+			}
+			if t.labels != nil {
+				t.labels[j] = label
+			}
+		}
+	}
+}
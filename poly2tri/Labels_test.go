@@ -0,0 +1,51 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+// labelledLength returns the length of the sides of the triangles with label, failing t
+// when one of them is not on the line x = 10.
+func labelledLength(t *testing.T, triangles []*Triangle, label int) float64 {
+	t.Helper()
+	length := 0.0
+	for i := 0; i < len(triangles); i++ {
+		for j := 0; j < 3; j++ {
+			if triangles[i].GetEdgeLabel(j) != label {
+				continue
+			}
+			p, q := triangles[i].points[(j+1)%3], triangles[i].points[(j+2)%3]
+			if p.x != 10 || q.x != 10 {
+				t.Errorf("side %v %v is labelled", p, q)
+			}
+			length += math.Abs(q.y - p.y)
+		}
+	}
+	return length
+}
+
+func TestEdgeLabels(t *testing.T) {
+	tcx := &SweepContext{}
+	tcx.InitRings([][]*Point{rectangle(0, 0, 10, 10), rectangle(10, 0, 20, 10)}, FillNonZero)
+	tcx.SetEdgeLabel(NewPoint64(10, 10), NewPoint64(10, 0), 5)
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	// Both sides of the edge get the label
+	if length := labelledLength(t, tcx.GetTriangles(), 5); length != 20 {
+		t.Errorf("got a labelled length of %v, want 20", length)
+	}
+	if err := tcx.InsertPoint(NewPoint64(10, 3.3)); err != nil {
+		t.Fatal(err)
+	}
+	if length := labelledLength(t, tcx.GetTriangles(), 5); math.Abs(length-20) > 1e-9 {
+		t.Errorf("got a labelled length of %v after InsertPoint, want 20", length)
+	}
+	if err := tcx.Refine(RefineOptions{MinAngle: 25, MaxArea: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if length := labelledLength(t, tcx.GetTriangles(), 5); math.Abs(length-20) > 1e-9 {
+		t.Errorf("got a labelled length of %v after Refine, want 20", length)
+	}
+}
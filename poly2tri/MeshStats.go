@@ -0,0 +1,157 @@
+package poly2tri
+
+import (
+	"math"
+	"strconv"
+)
+
+// Distribution sums up the values of a measure over the triangles. Counts[i] is the
+// number of values from Bounds[i] up to Bounds[i+1], excluded but for the last bucket,
+// which also gets the values beyond it. There are no buckets without Bounds.
+type Distribution struct {
+	Min    float64
+	Max    float64
+	Mean   float64
+	Bounds []float64
+	Counts []int
+}
+
+func newDistribution(bounds []float64) *Distribution {
+	d := &Distribution{math.Inf(1), math.Inf(-1), 0, bounds, nil}
+	if len(bounds) > 1 {
+		d.Counts = make([]int, len(bounds)-1)
+	}
+	return d
+}
+
+func (this *Distribution) add(value float64) {
+	this.Min = math.Min(this.Min, value)
+	this.Max = math.Max(this.Max, value)
+	this.Mean += value
+	if this.Counts == nil {
+		return
+	}
+	i := 0
+	for i < len(this.Counts)-1 && !(value < this.Bounds[i+1]) {
+		i++
+	}
+	this.Counts[i]++
+}
+
+func (this *Distribution) finish(n int) {
+	if n == 0 {
+		this.Min, this.Max = 0, 0
+		return
+	}
+	this.Mean /= float64(n)
+}
+
+func (this *Distribution) String() string {
+	str := "min " + formatStat(this.Min) + " max " + formatStat(this.Max) + " mean " + formatStat(this.Mean) + "\n"
+	for i := 0; i < len(this.Counts); i++ {
+		str += "  " + formatStat(this.Bounds[i]) + " - " + formatStat(this.Bounds[i+1]) + ": " + strconv.Itoa(this.Counts[i]) + "\n"
+	}
+	return str
+}
+
+func formatStat(value float64) string {
+	return strconv.FormatFloat(value, 'g', 4, 64)
+}
+
+// MeshStats measures the quality of a triangle mesh.
+type MeshStats struct {
+	Triangles int
+	Area      float64
+	// Angles holds the angles of the triangles in degrees, by steps of 10.
+	Angles *Distribution
+	// AspectRatios holds the longest side over the shortest height of each triangle, scaled
+	// to 1 for the equilateral triangle. It grows without bound as a triangle flattens.
+	AspectRatios *Distribution
+	// RadiusRatios holds twice the inradius over the circumradius of each triangle, 1 for
+	// the equilateral triangle down to 0 for a flat one.
+	RadiusRatios *Distribution
+	// EdgeLengths holds the length of each side, counted once for two triangles.
+	EdgeLengths      *Distribution
+	Edges            int
+	ConstrainedEdges int
+	// NonDelaunayEdges counts the unconstrained sides between two of the triangles that
+	// have the point opposite to the side in one triangle strictly inside the circumcircle
+	// of the other.
+	NonDelaunayEdges int
+}
+
+// NewMeshStats measures triangles, like the ones of SweepContext.GetTriangles.
+func NewMeshStats(triangles []*Triangle) *MeshStats {
+	stats := &MeshStats{}
+	stats.Triangles = len(triangles)
+	angles := []float64{}
+	for a := 0.0; a <= 180; a += 10 {
+		angles = append(angles, a)
+	}
+	stats.Angles = newDistribution(angles)
+	stats.AspectRatios = newDistribution([]float64{1, 1.5, 2, 3, 5, 10, math.Inf(1)})
+	stats.RadiusRatios = newDistribution([]float64{0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1})
+	stats.EdgeLengths = newDistribution(nil)
+	index := make(map[*Triangle]int)
+	for i := 0; i < len(triangles); i++ {
+		index[triangles[i]] = i
+	}
+	for i := 0; i < len(triangles); i++ {
+		t := triangles[i]
+		area := math.Abs(t.area())
+		stats.Area += area
+		// lengths[j] is the side across points[j]
+		lengths := make([]float64, 3)
+		for j := 0; j < 3; j++ {
+			p, q := t.points[(j+1)%3], t.points[(j+2)%3]
+			lengths[j] = math.Hypot(q.x-p.x, q.y-p.y)
+		}
+		for j := 0; j < 3; j++ {
+			a, b, c := lengths[j], lengths[(j+1)%3], lengths[(j+2)%3]
+			cos := (b*b + c*c - a*a) / (2 * b * c)
+			stats.Angles.add(math.Acos(math.Max(-1, math.Min(1, cos))) * 180 / math.Pi)
+		}
+		longest := math.Max(lengths[0], math.Max(lengths[1], lengths[2]))
+		perimeter := lengths[0] + lengths[1] + lengths[2]
+		if area > 0 {
+			stats.AspectRatios.add(longest * longest * math.Sqrt(3) / (4 * area))
+			// r = 2A/perimeter and R = abc/4A
+			stats.RadiusRatios.add(16 * area * area / (perimeter * lengths[0] * lengths[1] * lengths[2]))
+		} else {
+			stats.AspectRatios.add(math.Inf(1))
+			stats.RadiusRatios.add(0)
+		}
+		for j := 0; j < 3; j++ {
+			k, shared := index[t.neighbors[j]]
+			if shared && k < i {
+				// Counted with the other triangle
+				continue
+			}
+			stats.Edges++
+			stats.EdgeLengths.add(lengths[j])
+			if t.constrained_edge[j] {
+				stats.ConstrainedEdges++
+			} else if shared {
+				if incircleSign(t.points[0], t.points[1], t.points[2], t.neighbors[j].oppositePoint(t, t.points[j])) > 0 {
+					stats.NonDelaunayEdges++
+				}
+			}
+		}
+	}
+	stats.Angles.finish(3 * stats.Triangles)
+	stats.AspectRatios.finish(stats.Triangles)
+	stats.RadiusRatios.finish(stats.Triangles)
+	stats.EdgeLengths.finish(stats.Edges)
+	return stats
+}
+
+// String returns a report of the statistics, one measure after the other.
+func (this *MeshStats) String() string {
+	str := "triangles " + strconv.Itoa(this.Triangles) + " area " + formatStat(this.Area) + "\n"
+	str += "edges " + strconv.Itoa(this.Edges) + " constrained " + strconv.Itoa(this.ConstrainedEdges) + " non-Delaunay " + strconv.Itoa(this.NonDelaunayEdges) + "\n"
+	str += "edge lengths " + this.EdgeLengths.String()
+	str += "angles " + this.Angles.String()
+	str += "aspect ratios " + this.AspectRatios.String()
+	str += "radius ratios " + this.RadiusRatios.String()
+	return str
+}
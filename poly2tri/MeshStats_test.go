@@ -0,0 +1,97 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+// kite returns the two triangles of the kite 0,0 2,-1 4,0 2,1 split along its long
+// diagonal, which is not Delaunay.
+func kite() []*Triangle {
+	a, b, c, d := NewPoint64(0, 0), NewPoint64(2, -1), NewPoint64(4, 0), NewPoint64(2, 1)
+	t1, t2 := NewTriangle(a, b, c), NewTriangle(a, c, d)
+	t1.markNeighbor(t2)
+	return []*Triangle{t1, t2}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMeshStats(t *testing.T) {
+	triangles := kite()
+	stats := NewMeshStats(triangles)
+	if stats.Triangles != 2 || !near(stats.Area, 4) {
+		t.Errorf("got %d triangles of area %v, want 2 of area 4", stats.Triangles, stats.Area)
+	}
+	if stats.Edges != 5 || stats.ConstrainedEdges != 0 || stats.NonDelaunayEdges != 1 {
+		t.Errorf("got %d edges, %d constrained and %d non-Delaunay, want 5, 0 and 1", stats.Edges, stats.ConstrainedEdges, stats.NonDelaunayEdges)
+	}
+	// The angles at the ends of the diagonal, and the one at the side points
+	small := math.Atan(0.5) * 180 / math.Pi
+	angles := stats.Angles
+	if !near(angles.Min, small) || !near(angles.Max, 180-2*small) || !near(angles.Mean, 60) {
+		t.Errorf("got angles %v %v %v, want %v %v 60", angles.Min, angles.Max, angles.Mean, small, 180-2*small)
+	}
+	for i := 0; i < len(angles.Counts); i++ {
+		want := 0
+		if i == 2 {
+			want = 4
+		} else if i == 12 {
+			want = 2
+		}
+		if angles.Counts[i] != want {
+			t.Errorf("got %d angles from %v, want %d", angles.Counts[i], angles.Bounds[i], want)
+		}
+	}
+	lengths := stats.EdgeLengths
+	if !near(lengths.Min, math.Sqrt(5)) || !near(lengths.Max, 4) || !near(lengths.Mean, (4*math.Sqrt(5)+4)/5) {
+		t.Errorf("got edge lengths %v %v %v", lengths.Min, lengths.Max, lengths.Mean)
+	}
+	if aspect := stats.AspectRatios; !near(aspect.Min, 2*math.Sqrt(3)) || !near(aspect.Max, 2*math.Sqrt(3)) || aspect.Counts[3] != 2 {
+		t.Errorf("got aspect ratios %v %v and counts %v, want %v", aspect.Min, aspect.Max, aspect.Counts, 2*math.Sqrt(3))
+	}
+	// Twice the inradius 2A/p over the circumradius abc/4A
+	radius := 16 * 2 * 2 / ((4 + 2*math.Sqrt(5)) * 5 * 4)
+	if ratios := stats.RadiusRatios; !near(ratios.Min, radius) || !near(ratios.Mean, radius) || ratios.Counts[int(radius*10)] != 2 {
+		t.Errorf("got radius ratios %v %v and counts %v, want %v", ratios.Min, ratios.Mean, ratios.Counts, radius)
+	}
+	// A constrained diagonal is not counted as non-Delaunay
+	triangles[0].constrained_edge[1] = true
+	triangles[1].constrained_edge[2] = true
+	stats = NewMeshStats(triangles)
+	if stats.ConstrainedEdges != 1 || stats.NonDelaunayEdges != 0 {
+		t.Errorf("got %d constrained and %d non-Delaunay edges, want 1 and 0", stats.ConstrainedEdges, stats.NonDelaunayEdges)
+	}
+	// The points of a rectangle are on one circle, so either diagonal is Delaunay
+	p := rectangle(0, 0, 4, 3)
+	t1, t2 := NewTriangle(p[0], p[1], p[2]), NewTriangle(p[0], p[2], p[3])
+	t1.markNeighbor(t2)
+	stats = NewMeshStats([]*Triangle{t1, t2})
+	if stats.NonDelaunayEdges != 0 || !near(stats.Area, 12) || !near(stats.Angles.Max, 90) || !near(stats.Angles.Min, math.Atan(0.75)*180/math.Pi) {
+		t.Errorf("got %d non-Delaunay edges, area %v and angles %v to %v", stats.NonDelaunayEdges, stats.Area, stats.Angles.Min, stats.Angles.Max)
+	}
+	stats = NewMeshStats(nil)
+	if stats.Angles.Min != 0 || stats.Angles.Max != 0 || stats.Edges != 0 {
+		t.Errorf("got angles %v to %v and %d edges for no triangle", stats.Angles.Min, stats.Angles.Max, stats.Edges)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	d := newDistribution([]float64{0, 1, 2})
+	values := []float64{0.5, 1, 2, 5}
+	for i := 0; i < len(values); i++ {
+		d.add(values[i])
+	}
+	d.finish(len(values))
+	// A value on a bound goes up, and the last bucket takes the values beyond it
+	if d.Counts[0] != 1 || d.Counts[1] != 3 {
+		t.Errorf("got counts %v, want 1 3", d.Counts)
+	}
+	if d.Min != 0.5 || d.Max != 5 || d.Mean != 2.125 {
+		t.Errorf("got %v %v %v, want 0.5 5 2.125", d.Min, d.Max, d.Mean)
+	}
+	if d := newDistribution(nil); d.Counts != nil {
+		t.Errorf("got counts %v without bounds", d.Counts)
+	}
+}
@@ -5,7 +5,7 @@ type Node struct {
 	triangle *Triangle
 	next     *Node
 	prev     *Node
-	value    float32
+	value    float64
 }
 
 func NewNode(p *Point, t *Triangle) *Node {
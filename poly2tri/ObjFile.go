@@ -3,39 +3,24 @@ package poly2tri
 import (
 	"io/ioutil"
 	"os"
-	"sort"
 	"strconv"
 )
 
 func SaveOBJ(path string, triangles []*Triangle) {
-	id := 0
+	mesh := ToIndexedMesh(triangles, false)
+	vstr := ""
+	for i := 0; i < len(mesh.Points); i++ {
+		p := mesh.Points[i]
+		vstr += "v " + strconv.FormatFloat(p.x/20, 'f', -1, 64) + " 0.0 " + strconv.FormatFloat(-p.y/20, 'f', -1, 64) + " \n"
+	}
 	fstr := ""
-	maps := make(map[int]string)
-	for i := 0; i < len(triangles); i++ {
-		t := triangles[i]
-		ps := t.GetPoints()
+	for i := 0; i < len(mesh.Indices); i += 3 {
 		fstr += "f"
-		for p := 0; p < len(ps); p++ {
-			if ps[p].Id == 0 {
-				id++
-				ps[p].Id = id
-			}
-			fstr += " " + strconv.Itoa(ps[p].Id)
-			if !ps[p].Activation {
-				ps[p].Activation = true
-				maps[ps[p].Id] = ("v " + strconv.FormatFloat(float64(ps[p].X())/20, 'f', 4, 32) + " 0.0 " + strconv.FormatFloat(-float64(ps[p].Y())/20, 'f', 4, 32) + " \n")
-			}
+		for j := 0; j < 3; j++ {
+			// OBJ indices start at 1
+			fstr += " " + strconv.Itoa(int(mesh.Indices[i+j])+1)
 		}
 		fstr += "\n"
 	}
-	vstr := ""
-	list := []int{}
-	for k, _ := range maps {
-		list = append(list, k)
-	}
-	sort.IntSlice(list).Sort()
-	for i := 0; i < len(list); i++ {
-		vstr += maps[list[i]]
-	}
 	ioutil.WriteFile(path, []byte(vstr+fstr), os.ModePerm)
 }
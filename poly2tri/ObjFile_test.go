@@ -0,0 +1,42 @@
+package poly2tri
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSaveOBJPrecision(t *testing.T) {
+	x0, y0 := 500000.123456, 4649776.987654
+	tcx := &SweepContext{}
+	tcx.Init(rectangle(x0, y0, x0+0.01, y0+0.02))
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "mesh.obj")
+	SaveOBJ(path, tcx.GetTriangles())
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mesh := ToIndexedMesh(tcx.GetTriangles(), false)
+	vertices := 0
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+		if len(fields) != 4 || fields[0] != "v" {
+			continue
+		}
+		x, _ := strconv.ParseFloat(fields[1], 64)
+		y, _ := strconv.ParseFloat(fields[3], 64)
+		if p := mesh.Points[vertices]; x != p.x/20 || y != -p.y/20 {
+			t.Errorf("got vertex %v %v, want %v %v", x, y, p.x/20, -p.y/20)
+		}
+		vertices++
+	}
+	if vertices != len(mesh.Points) {
+		t.Errorf("got %d vertices, want %d", vertices, len(mesh.Points))
+	}
+}
@@ -8,36 +8,78 @@ import (
 )
 
 type Point struct {
-	x             float32
-	y             float32
-	p2t_edge_list []*Edge
-	Activation    bool
-	Id            int
+	x    float64
+	y    float64
+	data []float64
 }
 
 func NewPoint(x, y float32) *Point {
-	return &Point{x, y, nil, false, 0}
+	return NewPoint64(float64(x), float64(y))
+}
+
+// NewPoint64 creates a point keeping the full float64 precision through the whole pipeline.
+func NewPoint64(x, y float64) *Point {
+	return &Point{x, y, nil}
+}
+
+// NewPointData creates a point carrying values like a height, texture coordinates or a
+// color. The points created by the library get the values interpolated from the points
+// around them, when these all carry as many values.
+func NewPointData(x, y float64, data ...float64) *Point {
+	return &Point{x, y, append([]float64{}, data...)}
+}
+
+// Data returns the values carried by the point, nil if it has none. They must not be modified.
+func (this *Point) Data() []float64 {
+	return this.data
+}
+
+// interpolate gives the point the sum of the values of points weighted by weights.
+func (this *Point) interpolate(points []*Point, weights []float64) {
+	n := len(points[0].data)
+	for i := 1; i < len(points); i++ {
+		if len(points[i].data) != n {
+			return
+		}
+	}
+	if n == 0 {
+		return
+	}
+	this.data = make([]float64, n)
+	for i := 0; i < len(points); i++ {
+		for j := 0; j < n; j++ {
+			this.data[j] += points[i].data[j] * weights[i]
+		}
+	}
 }
 func (this *Point) toString() string {
-	return strconv.FormatFloat(float64(this.x), 'f', 4, 32) + "," + strconv.FormatFloat(float64(this.y), 'f', 4, 32)
+	return strconv.FormatFloat(this.x, 'f', 4, 64) + "," + strconv.FormatFloat(this.y, 'f', 4, 64)
 }
 
 func (this *Point) X() float32 {
-	return this.x
+	return float32(this.x)
 }
 
 func (this *Point) Y() float32 {
+	return float32(this.y)
+}
+
+func (this *Point) X64() float64 {
+	return this.x
+}
+
+func (this *Point) Y64() float64 {
 	return this.y
 }
 
 func (this *Point) clone() *Point {
-	return NewPoint(this.x, this.y)
+	return NewPoint64(this.x, this.y)
 }
 func (this *Point) set_zero() {
 	this.x = 0
 	this.y = 0
 }
-func (this *Point) set(x, y float32) {
+func (this *Point) set(x, y float64) {
 	this.x = x
 	this.y = y
 }
@@ -53,14 +95,14 @@ func (this *Point) sub(n *Point) {
 	this.x -= n.x
 	this.y -= n.y
 }
-func (this *Point) mul(s float32) {
+func (this *Point) mul(s float64) {
 	this.x *= s
 	this.y *= s
 }
-func (this *Point) length() float32 {
-	return float32(math.Sqrt(float64(this.x*this.x + this.y*this.y)))
+func (this *Point) length() float64 {
+	return math.Sqrt(this.x*this.x + this.y*this.y)
 }
-func (this *Point) normalize() float32 {
+func (this *Point) normalize() float64 {
 	var l = this.length()
 	this.x /= l
 	this.y /= l
@@ -83,14 +125,13 @@ func StringConvertPoint(path string) [][]*Point {
 				ps = strings.Split(strlist[i], ",")
 			}
 			for j := 0; j < len(ps)/2; j++ {
-				x, _ := strconv.ParseFloat(ps[j*2], 32)
-				y, _ := strconv.ParseFloat(ps[j*2+1], 32)
-				list[i] = append(list[i], NewPoint(float32(x), float32(y)))
+				x, _ := strconv.ParseFloat(ps[j*2], 64)
+				y, _ := strconv.ParseFloat(ps[j*2+1], 64)
+				list[i] = append(list[i], NewPoint64(x, y))
 			}
 		}
 		return list
 	} else {
 		panic(err.Error())
 	}
-	return [][]*Point{}
 }
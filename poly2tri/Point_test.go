@@ -0,0 +1,56 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPointData(t *testing.T) {
+	// The values are a linear function of the place, which interpolation keeps exactly
+	f := func(x, y float64) float64 { return 2*x + 3*y + 1 }
+	corner := func(x, y float64) *Point { return NewPointData(x, y, f(x, y), 7) }
+	contour := []*Point{corner(0, 0), corner(20, 0), corner(20, 10), corner(0, 10)}
+	hole := []*Point{corner(5, 4), corner(5, 6), corner(8, 6), corner(8, 4)}
+	steiner := corner(15, 5)
+	tcx := &SweepContext{}
+	tcx.Init(contour)
+	tcx.AddHole(hole)
+	tcx.AddPoint(steiner)
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.Refine(RefineOptions{MinAngle: 25, MaxArea: 2}); err != nil {
+		t.Fatal(err)
+	}
+	created := 0
+	points := ToIndexedMesh(tcx.GetTriangles(), false).Points
+	for i := 0; i < len(points); i++ {
+		p := points[i]
+		if tcx.InputIndex(p) < 0 {
+			created++
+		}
+		data := p.Data()
+		if len(data) != 2 || math.Abs(data[0]-f(p.x, p.y)) > 1e-9 || math.Abs(data[1]-7) > 1e-9 {
+			t.Errorf("point %v has the values %v, want %v 7", p, data, f(p.x, p.y))
+		}
+	}
+	if created == 0 {
+		t.Fatal("the refinement created no point")
+	}
+	for i := 0; i < len(contour); i++ {
+		if got := tcx.InputIndex(contour[i]); got != i {
+			t.Errorf("contour point %d has the index %d", i, got)
+		}
+	}
+	for i := 0; i < len(hole); i++ {
+		if got := tcx.InputIndex(hole[i]); got != 4+i {
+			t.Errorf("hole point %d has the index %d, want %d", i, got, 4+i)
+		}
+	}
+	if got := tcx.InputIndex(steiner); got != 8 {
+		t.Errorf("the added point has the index %d, want 8", got)
+	}
+	if got := tcx.InputIndex(NewPoint64(0, 0)); got != -1 {
+		t.Errorf("a point of the caller that was not given has the index %d", got)
+	}
+}
@@ -0,0 +1,279 @@
+package poly2tri
+
+import (
+	"math"
+	"math/big"
+)
+
+// Predicates selects the arithmetic used for the geometric tests of the sweep and of the point location.
+type Predicates int
+
+const (
+	// RobustPredicates evaluates in float64 with Shewchuk's error bounds and falls back
+	// to exact arithmetic when the sign can't be trusted. This is the default.
+	RobustPredicates Predicates = iota
+	// FastPredicates is the original naive arithmetic with EPSILON.
+	FastPredicates
+)
+
+var (
+	epsilon      = math.Ldexp(1, -53)
+	ccwerrboundA = (3 + 16*epsilon) * epsilon
+	iccerrboundA = (10 + 96*epsilon) * epsilon
+)
+
+func (this Predicates) orient2d(pa, pb, pc *Point) int {
+	switch this.orientation(pa, pb, pc) {
+	case 1:
+		return CCW
+	case -1:
+		return CW
+	}
+	return COLLINEAR
+}
+
+// orientation returns 1 when pa,pb,pc are counterclockwise, -1 when they are clockwise
+// and 0 when they are collinear.
+func (this Predicates) orientation(pa, pb, pc *Point) int {
+	if this == FastPredicates {
+		val := (pa.x-pc.x)*(pb.y-pc.y) - (pa.y-pc.y)*(pb.x-pc.x)
+		if val > -(EPSILON) && val < (EPSILON) {
+			return 0
+		}
+		return sign(val)
+	}
+	return orient2dSign(pa, pb, pc)
+}
+
+// incircle returns 1 when pd is inside the circle through the counterclockwise pa,pb,pc,
+// -1 when it is outside and 0 when it is on the circle.
+func (this Predicates) incircle(pa, pb, pc, pd *Point) int {
+	if this == FastPredicates {
+		adx, ady := pa.x-pd.x, pa.y-pd.y
+		bdx, bdy := pb.x-pd.x, pb.y-pd.y
+		cdx, cdy := pc.x-pd.x, pc.y-pd.y
+		alift := adx*adx + ady*ady
+		blift := bdx*bdx + bdy*bdy
+		clift := cdx*cdx + cdy*cdy
+		return sign(alift*(bdx*cdy-cdx*bdy) + blift*(cdx*ady-adx*cdy) + clift*(adx*bdy-bdx*ady))
+	}
+	return incircleSign(pa, pb, pc, pd)
+}
+
+/**
+ * Same contract as Sweep.inCircle: d has to be in area B of the triangle a,b,c
+ * and strictly inside its circumcircle.
+ */
+func (this Predicates) inCircle(pa, pb, pc, pd *Point) bool {
+	if this == FastPredicates {
+		adx := pa.x - pd.x
+		ady := pa.y - pd.y
+		bdx := pb.x - pd.x
+		bdy := pb.y - pd.y
+		adxbdy := adx * bdy
+		bdxady := bdx * ady
+		oabd := adxbdy - bdxady
+		if oabd <= 0 {
+			return false
+		}
+		cdx := pc.x - pd.x
+		cdy := pc.y - pd.y
+		cdxady := cdx * ady
+		adxcdy := adx * cdy
+		ocad := cdxady - adxcdy
+		if ocad <= 0 {
+			return false
+		}
+		bdxcdy := bdx * cdy
+		cdxbdy := cdx * bdy
+		alift := adx*adx + ady*ady
+		blift := bdx*bdx + bdy*bdy
+		clift := cdx*cdx + cdy*cdy
+		det := alift*(bdxcdy-cdxbdy) + blift*ocad + clift*oabd
+		return det > 0
+	}
+	if orient2dSign(pa, pb, pd) <= 0 || orient2dSign(pc, pa, pd) <= 0 {
+		return false
+	}
+	return incircleSign(pa, pb, pc, pd) > 0
+}
+
+func (this Predicates) inScanArea(pa, pb, pc, pd *Point) bool {
+	if this == FastPredicates {
+		oadb := (pa.x-pb.x)*(pd.y-pb.y) - (pd.x-pb.x)*(pa.y-pb.y)
+		if oadb >= -EPSILON {
+			return false
+		}
+		oadc := (pa.x-pc.x)*(pd.y-pc.y) - (pd.x-pc.x)*(pa.y-pc.y)
+		if oadc <= EPSILON {
+			return false
+		}
+		return true
+	}
+	return orient2dSign(pa, pd, pb) < 0 && orient2dSign(pa, pd, pc) > 0
+}
+
+// pointInsideTriangle tells if pp is inside or on the border of the triangle p1,p2,p3 of any orientation.
+func (this Predicates) pointInsideTriangle(p1, p2, p3, pp *Point) bool {
+	if this == FastPredicates {
+		if product(p1, p2, p3) >= 0 {
+			return product(p1, p2, pp) >= 0 && product(p2, p3, pp) >= 0 && product(p3, p1, pp) >= 0
+		} else {
+			return product(p1, p2, pp) <= 0 && product(p2, p3, pp) <= 0 && product(p3, p1, pp) <= 0
+		}
+	}
+	if orient2dSign(p1, p2, p3) >= 0 {
+		return orient2dSign(p1, p2, pp) >= 0 && orient2dSign(p2, p3, pp) >= 0 && orient2dSign(p3, p1, pp) >= 0
+	} else {
+		return orient2dSign(p1, p2, pp) <= 0 && orient2dSign(p2, p3, pp) <= 0 && orient2dSign(p3, p1, pp) <= 0
+	}
+}
+
+// orient2dSign returns the sign of (pa-pc)x(pb-pc): 1 when pa,pb,pc are counterclockwise.
+func orient2dSign(pa, pb, pc *Point) int {
+	detleft := (pa.x - pc.x) * (pb.y - pc.y)
+	detright := (pa.y - pc.y) * (pb.x - pc.x)
+	det := detleft - detright
+	// Terms of opposite signs (or a zero term) can't cancel out
+	if detleft > 0 {
+		if detright <= 0 {
+			return sign(det)
+		}
+	} else if detleft < 0 {
+		if detright >= 0 {
+			return sign(det)
+		}
+	} else {
+		return sign(det)
+	}
+	errbound := ccwerrboundA * (math.Abs(detleft) + math.Abs(detright))
+	if det > errbound || -det > errbound {
+		return sign(det)
+	}
+	return orient2dExact(pa, pb, pc)
+}
+
+// incircleSign returns 1 when pd is inside the circle through the counterclockwise pa,pb,pc.
+func incircleSign(pa, pb, pc, pd *Point) int {
+	adx := pa.x - pd.x
+	ady := pa.y - pd.y
+	bdx := pb.x - pd.x
+	bdy := pb.y - pd.y
+	cdx := pc.x - pd.x
+	cdy := pc.y - pd.y
+	bdxcdy := bdx * cdy
+	cdxbdy := cdx * bdy
+	alift := adx*adx + ady*ady
+	cdxady := cdx * ady
+	adxcdy := adx * cdy
+	blift := bdx*bdx + bdy*bdy
+	adxbdy := adx * bdy
+	bdxady := bdx * ady
+	clift := cdx*cdx + cdy*cdy
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+	errbound := iccerrboundA * permanent
+	if det > errbound || -det > errbound {
+		return sign(det)
+	}
+	return incircleExact(pa, pb, pc, pd)
+}
+
+func orient2dExact(pa, pb, pc *Point) int {
+	// Integer like coordinates usually don't need rational arithmetic
+	e := &exactFloat{true}
+	det := e.sub(e.mul(e.sub(pa.x, pc.x), e.sub(pb.y, pc.y)),
+		e.mul(e.sub(pa.y, pc.y), e.sub(pb.x, pc.x)))
+	if e.exact {
+		return sign(det)
+	}
+	acx := ratSub(pa.x, pc.x)
+	bcy := ratSub(pb.y, pc.y)
+	acy := ratSub(pa.y, pc.y)
+	bcx := ratSub(pb.x, pc.x)
+	left := new(big.Rat).Mul(acx, bcy)
+	right := new(big.Rat).Mul(acy, bcx)
+	return left.Cmp(right)
+}
+
+func incircleExact(pa, pb, pc, pd *Point) int {
+	e := &exactFloat{true}
+	fadx, fady := e.sub(pa.x, pd.x), e.sub(pa.y, pd.y)
+	fbdx, fbdy := e.sub(pb.x, pd.x), e.sub(pb.y, pd.y)
+	fcdx, fcdy := e.sub(pc.x, pd.x), e.sub(pc.y, pd.y)
+	fdet := e.mul(e.lift(fadx, fady), e.cross(fbdx, fbdy, fcdx, fcdy))
+	fdet = e.add(fdet, e.mul(e.lift(fbdx, fbdy), e.cross(fcdx, fcdy, fadx, fady)))
+	fdet = e.add(fdet, e.mul(e.lift(fcdx, fcdy), e.cross(fadx, fady, fbdx, fbdy)))
+	if e.exact {
+		return sign(fdet)
+	}
+	adx, ady := ratSub(pa.x, pd.x), ratSub(pa.y, pd.y)
+	bdx, bdy := ratSub(pb.x, pd.x), ratSub(pb.y, pd.y)
+	cdx, cdy := ratSub(pc.x, pd.x), ratSub(pc.y, pd.y)
+	det := new(big.Rat).Mul(ratLift(adx, ady), ratCross(bdx, bdy, cdx, cdy))
+	det.Add(det, new(big.Rat).Mul(ratLift(bdx, bdy), ratCross(cdx, cdy, adx, ady)))
+	det.Add(det, new(big.Rat).Mul(ratLift(cdx, cdy), ratCross(adx, ady, bdx, bdy)))
+	return det.Sign()
+}
+
+func ratSub(a, b float64) *big.Rat {
+	ra := new(big.Rat).SetFloat64(a)
+	return ra.Sub(ra, new(big.Rat).SetFloat64(b))
+}
+
+// ratLift returns x*x + y*y.
+func ratLift(x, y *big.Rat) *big.Rat {
+	lift := new(big.Rat).Mul(x, x)
+	return lift.Add(lift, new(big.Rat).Mul(y, y))
+}
+
+// ratCross returns ax*by - bx*ay.
+func ratCross(ax, ay, bx, by *big.Rat) *big.Rat {
+	cross := new(big.Rat).Mul(ax, by)
+	return cross.Sub(cross, new(big.Rat).Mul(bx, ay))
+}
+
+// exactFloat runs float64 operations and remembers if any of them had to round.
+type exactFloat struct {
+	exact bool
+}
+
+func (this *exactFloat) mul(a, b float64) float64 {
+	p := a * b
+	if math.IsInf(p, 0) || math.FMA(a, b, -p) != 0 {
+		this.exact = false
+	}
+	return p
+}
+
+func (this *exactFloat) add(a, b float64) float64 {
+	s := a + b
+	bv := s - a
+	if math.IsInf(s, 0) || (a-(s-bv))+(b-bv) != 0 {
+		this.exact = false
+	}
+	return s
+}
+
+func (this *exactFloat) sub(a, b float64) float64 {
+	return this.add(a, -b)
+}
+
+func (this *exactFloat) lift(x, y float64) float64 {
+	return this.add(this.mul(x, x), this.mul(y, y))
+}
+
+func (this *exactFloat) cross(ax, ay, bx, by float64) float64 {
+	return this.sub(this.mul(ax, by), this.mul(bx, ay))
+}
+
+func sign(v float64) int {
+	if v > 0 {
+		return 1
+	} else if v < 0 {
+		return -1
+	}
+	return 0
+}
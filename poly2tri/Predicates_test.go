@@ -0,0 +1,55 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrientation(t *testing.T) {
+	a, b := NewPoint64(0, 0), NewPoint64(1, 0)
+	c := NewPoint64(0.5, 1e-13)
+	if o := RobustPredicates.orientation(a, b, c); o != 1 {
+		t.Errorf("robust orientation %d, want 1", o)
+	}
+	if o := FastPredicates.orientation(a, b, c); o != 0 {
+		t.Errorf("fast orientation %d, want 0 within EPSILON", o)
+	}
+}
+
+func TestIncircle(t *testing.T) {
+	a, b, c := NewPoint64(0, 0), NewPoint64(2, 0), NewPoint64(0, 2)
+	for _, predicates := range []Predicates{RobustPredicates, FastPredicates} {
+		if s := predicates.incircle(a, b, c, NewPoint64(1, 1)); s != 1 {
+			t.Errorf("%d: center %d, want 1", predicates, s)
+		}
+		if s := predicates.incircle(a, b, c, NewPoint64(2, 2)); s != 0 {
+			t.Errorf("%d: cocircular %d, want 0", predicates, s)
+		}
+		if s := predicates.incircle(a, b, c, NewPoint64(3, 3)); s != -1 {
+			t.Errorf("%d: outside %d, want -1", predicates, s)
+		}
+	}
+}
+
+func TestFastPredicatesContext(t *testing.T) {
+	tcx := &SweepContext{}
+	tcx.SetPredicates(FastPredicates)
+	tcx.Init(rectangle(0, 0, 10, 10))
+	tcx.AddHole(rectangle(4, 4, 6, 6))
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.InsertPoint(NewPoint64(2, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.InsertConstraint(tcx.points[0], NewPoint64(2, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.Refine(RefineOptions{MinAngle: 25}); err != nil {
+		t.Fatal(err)
+	}
+	checkMesh(t, tcx.GetTriangles())
+	if area := meshArea(tcx.GetTriangles()); math.Abs(area-96) > 1e-9 {
+		t.Errorf("got area %v, want 96", area)
+	}
+}
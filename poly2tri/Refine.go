@@ -0,0 +1,358 @@
+package poly2tri
+
+import "math"
+
+// MaxMinAngle is the largest RefineOptions.MinAngle, in degrees. Above it the refinement
+// usually inserts points until memory runs out.
+const MaxMinAngle = 33.8
+
+// RefineOptions sets the quality asked from SweepContext.Refine.
+type RefineOptions struct {
+	// MinAngle is the smallest angle allowed in a triangle, in degrees, 0 to ignore it.
+	// It can't be above MaxMinAngle; up to about 20.7 the refinement always terminates
+	// and up to MaxMinAngle it usually does. Angles between two input edges can't be
+	// improved.
+	MinAngle float64
+	// MaxArea is the largest area allowed for a triangle, 0 to ignore it.
+	MaxArea float64
+	// ConformingDelaunay also splits the constrained edges until every piece is an edge of
+	// the plain Delaunay triangulation of the points, so that no point of the mesh lies
+	// inside the circumcircle of a triangle.
+	ConformingDelaunay bool
+	// MaxSteinerPoints stops the refinement once that many points were inserted, 0 for no
+	// limit.
+	MaxSteinerPoints int
+}
+
+// Refine inserts Steiner points in the triangulation until its triangles meet opts,
+// using Ruppert's algorithm: circumcenters of bad triangles are inserted unless they
+// encroach upon a constrained edge, which is split instead. Constrained edges stay
+// constrained once split and holes stay empty.
+//
+// Triangulate has to succeed first; GetTriangles then returns the refined mesh. The
+// mesh is left valid when a *SteinerLimitError is returned. An *OptionError tells that
+// opts are out of range, the mesh being left as it was. Triangulating again drops the
+// Steiner points.
+func (this *SweepContext) Refine(opts RefineOptions) (err error) {
+	if len(this.triangles) == 0 {
+		return &InternalError{Op: "SweepContext.Refine() (not triangulated)"}
+	}
+	if !(opts.MinAngle >= 0 && opts.MinAngle <= MaxMinAngle) {
+		return &OptionError{Op: "SweepContext.Refine()", Option: "MinAngle", Reason: "not between 0 and MaxMinAngle"}
+	}
+	if !(opts.MaxArea >= 0) {
+		return &OptionError{Op: "SweepContext.Refine()", Option: "MaxArea", Reason: "negative"}
+	}
+	if opts.MaxSteinerPoints < 0 {
+		return &OptionError{Op: "SweepContext.Refine()", Option: "MaxSteinerPoints", Reason: "negative"}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = this.recoverError(r)
+		}
+		this.collectTriangles()
+	}()
+	// Ruppert's algorithm expects a constrained Delaunay triangulation to start with
+	this.legalizeAll()
+	refiner := &refiner{tcx: this, opts: opts}
+	if opts.MinAngle > 0 {
+		refiner.sinAngle = math.Sin(opts.MinAngle * math.Pi / 180)
+	}
+	refiner.minLength = 1e-9 * math.Hypot(this.pmax.x-this.pmin.x, this.pmax.y-this.pmin.y)
+	return refiner.run()
+}
+
+// refineSegment is a constrained side p-q of t waiting to be split.
+type refineSegment struct {
+	t *Triangle
+	p *Point
+	q *Point
+}
+
+type refiner struct {
+	tcx       *SweepContext
+	opts      RefineOptions
+	sinAngle  float64
+	minLength float64
+	inserted  int
+	segments  []*refineSegment
+}
+
+func (this *refiner) run() error {
+	for {
+		this.splitEncroached()
+		if this.limited() {
+			return &SteinerLimitError{Limit: this.opts.MaxSteinerPoints}
+		}
+		progress := false
+		bad := this.badTriangles()
+		for i := 0; i < len(bad); i++ {
+			if this.limited() {
+				return &SteinerLimitError{Limit: this.opts.MaxSteinerPoints}
+			}
+			// Earlier insertions may already have fixed or replaced it
+			if bad[i].interior && this.isBad(bad[i]) && this.refineTriangle(bad[i]) {
+				progress = true
+			}
+			this.splitQueue()
+		}
+		if !progress {
+			return nil
+		}
+	}
+}
+
+func (this *refiner) limited() bool {
+	return this.opts.MaxSteinerPoints > 0 && this.inserted >= this.opts.MaxSteinerPoints
+}
+
+// splitEncroached splits segments until no vertex of the mesh encroaches upon one.
+func (this *refiner) splitEncroached() {
+	for !this.limited() {
+		maps := this.tcx.maps
+		for i := 0; i < len(maps); i++ {
+			if maps[i].interior {
+				this.queueEncroached(maps[i])
+				if this.opts.ConformingDelaunay {
+					this.queueHidden(maps[i])
+				}
+			}
+		}
+		if len(this.segments) == 0 || !this.splitQueue() {
+			this.segments = this.segments[:0]
+			return
+		}
+	}
+}
+
+// splitQueue splits the queued segments and tells if any was split.
+func (this *refiner) splitQueue() bool {
+	split := false
+	for len(this.segments) > 0 && !this.limited() {
+		s := this.segments[len(this.segments)-1]
+		this.segments = this.segments[:len(this.segments)-1]
+		if this.splitSegment(s.t, s.p, s.q) {
+			split = true
+		}
+	}
+	return split
+}
+
+// queueEncroached queues the constrained sides of t whose diametral circle contains the opposite point.
+func (this *refiner) queueEncroached(t *Triangle) {
+	for i := 0; i < 3; i++ {
+		if t.constrained_edge[i] {
+			p, q := t.points[(i+1)%3], t.points[(i+2)%3]
+			if encroaches(p, q, t.points[i]) {
+				this.segments = append(this.segments, &refineSegment{t, p, q})
+			}
+		}
+	}
+}
+
+// queueHidden queues the constrained sides of t whose diametral circle contains a point
+// other than the opposite one. The search crosses segments, so points on the far side of
+// a segment or hidden behind another one count too.
+func (this *refiner) queueHidden(t *Triangle) {
+	for i := 0; i < 3; i++ {
+		p, q := t.points[(i+1)%3], t.points[(i+2)%3]
+		if !t.constrained_edge[i] || encroaches(p, q, t.points[i]) {
+			continue
+		}
+		cx, cy := (p.x+q.x)/2, (p.y+q.y)/2
+		r2 := ((q.x-p.x)*(q.x-p.x) + (q.y-p.y)*(q.y-p.y)) / 4
+		visited := map[*Triangle]bool{t: true}
+		queue := []*Triangle{t}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if n != t && this.encroachedIn(n, p, q) {
+				this.segments = append(this.segments, &refineSegment{t, p, q})
+				break
+			}
+			for k := 0; k < 3; k++ {
+				next := n.neighbors[k]
+				if next != nil && !visited[next] && segmentInCircle(n.points[(k+1)%3], n.points[(k+2)%3], cx, cy, r2) {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+}
+
+// encroachedIn tells if a point of t, leaving aside the sweep's head and tail, lies inside the circle of diameter p-q.
+func (this *refiner) encroachedIn(t *Triangle, p, q *Point) bool {
+	for i := 0; i < 3; i++ {
+		v := t.points[i]
+		if v != p && v != q && v != this.tcx.head && v != this.tcx.tail && encroaches(p, q, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *refiner) badTriangles() []*Triangle {
+	bad := []*Triangle{}
+	maps := this.tcx.maps
+	for i := 0; i < len(maps); i++ {
+		if maps[i].interior && this.isBad(maps[i]) {
+			bad = append(bad, maps[i])
+		}
+	}
+	return bad
+}
+
+func (this *refiner) isBad(t *Triangle) bool {
+	area := t.area()
+	if this.opts.MaxArea > 0 && area > this.opts.MaxArea {
+		return true
+	}
+	if this.sinAngle == 0 {
+		return false
+	}
+	lengths := [3]float64{}
+	k := 0
+	for i := 0; i < 3; i++ {
+		a, b := t.points[(i+1)%3], t.points[(i+2)%3]
+		lengths[i] = math.Hypot(b.x-a.x, b.y-a.y)
+		if lengths[i] < lengths[k] {
+			k = i
+		}
+	}
+	if lengths[k] < this.minLength {
+		return false
+	}
+	// The smallest angle is at points[k], nothing can be done when it is between two segments
+	if t.constrained_edge[(k+1)%3] && t.constrained_edge[(k+2)%3] {
+		return false
+	}
+	sin := 2 * area / (lengths[(k+1)%3] * lengths[(k+2)%3])
+	return sin < this.sinAngle
+}
+
+// refineTriangle inserts the circumcenter of t, or splits the segments it encroaches upon.
+func (this *refiner) refineTriangle(t *Triangle) bool {
+	c := t.circumcenter()
+	if math.IsNaN(c.x) || math.IsInf(c.x, 0) || math.IsNaN(c.y) || math.IsInf(c.y, 0) {
+		return false
+	}
+	found, i := this.tcx.walk(t, c, false)
+	if found == nil {
+		found = this.tcx.locate(c)
+	} else if i >= 0 {
+		// A segment hides the circumcenter from t
+		if found.constrained_edge[i] {
+			return this.splitSegment(found, found.points[(i+1)%3], found.points[(i+2)%3])
+		}
+		return false
+	}
+	if found == nil || !found.interior {
+		return false
+	}
+	if segments := this.encroachedBy(found, c); len(segments) > 0 {
+		split := false
+		for j := 0; j < len(segments); j++ {
+			if this.splitSegment(segments[j].t, segments[j].p, segments[j].q) {
+				split = true
+			}
+		}
+		return split
+	}
+	c.interpolate(found.points, found.barycentric(c))
+	star := this.tcx.insertIn(found, c)
+	if star == nil {
+		return false
+	}
+	this.inserted++
+	this.queueStar(star)
+	return true
+}
+
+// encroachedBy returns the segments bounding the cavity of c in which c would encroach.
+func (this *refiner) encroachedBy(t *Triangle, c *Point) []*refineSegment {
+	segments := []*refineSegment{}
+	visited := map[*Triangle]bool{t: true}
+	queue := []*Triangle{t}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for i := 0; i < 3; i++ {
+			p, q := t.points[(i+1)%3], t.points[(i+2)%3]
+			if t.constrained_edge[i] {
+				if encroaches(p, q, c) {
+					segments = append(segments, &refineSegment{t, p, q})
+				}
+				continue
+			}
+			n := t.neighbors[i]
+			if n != nil && !visited[n] && n.interior == t.interior && this.tcx.predicates.incircle(n.points[0], n.points[1], n.points[2], c) > 0 {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return segments
+}
+
+// splitSegment splits the constrained side p-q of t if it is still there.
+func (this *refiner) splitSegment(t *Triangle, p, q *Point) bool {
+	i := t.edgeIndex(p, q)
+	if i < 0 || !t.constrained_edge[i] || this.limited() {
+		return false
+	}
+	if math.Hypot(q.x-p.x, q.y-p.y) < 2*this.minLength {
+		return false
+	}
+	m := this.splitPoint(p, q)
+	star := this.tcx.legalizeFan(m, this.tcx.splitEdge(t, i, m))
+	this.inserted++
+	this.queueStar(star)
+	return true
+}
+
+// splitPoint returns the midpoint of p-q. When only one end is an input point the split
+// is at a power of two distance from it instead (concentric shells), so that segments
+// meeting at a small angle get split at matching distances and stop encroaching.
+func (this *refiner) splitPoint(p, q *Point) *Point {
+	_, inp := this.tcx.indices[p]
+	_, inq := this.tcx.indices[q]
+	t := 0.5
+	if inp != inq {
+		length := math.Hypot(q.x-p.x, q.y-p.y)
+		d := math.Exp2(math.Round(math.Log2(length / 2)))
+		if inp {
+			t = d / length
+		} else {
+			t = 1 - d/length
+		}
+	}
+	m := NewPoint64(p.x+(q.x-p.x)*t, p.y+(q.y-p.y)*t)
+	m.interpolate([]*Point{p, q}, []float64{1 - t, t})
+	return m
+}
+
+// queueStar queues the segments encroached upon in the triangles around a new point.
+func (this *refiner) queueStar(star []*Triangle) {
+	for i := 0; i < len(star); i++ {
+		if star[i].interior {
+			this.queueEncroached(star[i])
+		}
+	}
+}
+
+// segmentInCircle tells if the segment a-b passes strictly inside the circle of center cx,cy and squared radius r2.
+func segmentInCircle(a, b *Point, cx, cy, r2 float64) bool {
+	dx, dy := b.x-a.x, b.y-a.y
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((cx-a.x)*dx+(cy-a.y)*dy)/l))
+	}
+	x, y := a.x+t*dx-cx, a.y+t*dy-cy
+	return x*x+y*y < r2
+}
+
+// encroaches tells if v lies strictly inside the circle of diameter p-q.
+func encroaches(p, q, v *Point) bool {
+	return (p.x-v.x)*(q.x-v.x)+(p.y-v.y)*(q.y-v.y) < 0
+}
@@ -0,0 +1,141 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+// smallestAngle returns the smallest angle of t in degrees.
+func smallestAngle(t *Triangle) float64 {
+	angle := 180.0
+	for i := 0; i < 3; i++ {
+		a, b, c := t.points[i], t.points[(i+1)%3], t.points[(i+2)%3]
+		ux, uy := b.x-a.x, b.y-a.y
+		vx, vy := c.x-a.x, c.y-a.y
+		angle = math.Min(angle, math.Abs(math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy))*180/math.Pi)
+	}
+	return angle
+}
+
+// refinedPolygon returns a triangulated L shaped room with a pillar, whose angles are
+// all 90, 135 or 270 degrees.
+func refinedPolygon(t *testing.T) *SweepContext {
+	tcx := &SweepContext{}
+	tcx.Init([]*Point{NewPoint64(0, 0), NewPoint64(40, 0), NewPoint64(40, 10), NewPoint64(15, 10), NewPoint64(15, 30), NewPoint64(5, 30), NewPoint64(0, 25)})
+	tcx.AddHole(rectangle(5, 3, 7, 5))
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	return tcx
+}
+
+func TestRefineQuality(t *testing.T) {
+	const area = 40*10 + 15*20 - 5*5/2.0 - 4
+	options := []RefineOptions{
+		{MinAngle: 20},
+		{MinAngle: 30, MaxArea: 5},
+		{MaxArea: 0.5},
+		{MinAngle: 25, ConformingDelaunay: true},
+	}
+	for i := 0; i < len(options); i++ {
+		opts := options[i]
+		tcx := refinedPolygon(t)
+		before := len(tcx.GetTriangles())
+		if err := tcx.Refine(opts); err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		}
+		triangles := tcx.GetTriangles()
+		if len(triangles) <= before {
+			t.Errorf("%+v: got %d triangles, no more than the %d before", opts, len(triangles), before)
+		}
+		checkMesh(t, triangles)
+		if a := meshArea(triangles); math.Abs(a-area) > 1e-9 {
+			t.Errorf("%+v: got area %v, want %v", opts, a, area)
+		}
+		for j := 0; j < len(triangles); j++ {
+			if a := smallestAngle(triangles[j]); a < opts.MinAngle-1e-9 {
+				t.Errorf("%+v: triangle %v has an angle of %v degrees", opts, triangles[j].points, a)
+			}
+			if opts.MaxArea > 0 && triangles[j].area() > opts.MaxArea {
+				t.Errorf("%+v: triangle %v has an area of %v", opts, triangles[j].points, triangles[j].area())
+			}
+		}
+	}
+}
+
+func TestRefineConformingDelaunay(t *testing.T) {
+	// The apex of the hole is inside the circumcircle of the flat triangle below its long
+	// side, across the constrained side
+	tcx := &SweepContext{}
+	tcx.Init(rectangle(0, 0, 10, 10))
+	tcx.AddHole([]*Point{NewPoint64(3, 5), NewPoint64(5, 5.5), NewPoint64(7, 5)})
+	tcx.AddPoint(NewPoint64(5, 4.95))
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countNotDelaunay(tcx.GetTriangles()); n == 0 {
+		t.Fatal("the constrained triangulation is already Delaunay")
+	}
+	if err := tcx.Refine(RefineOptions{ConformingDelaunay: true}); err != nil {
+		t.Fatal(err)
+	}
+	checkMesh(t, tcx.GetTriangles())
+	if n := countNotDelaunay(tcx.GetTriangles()); n > 0 {
+		t.Errorf("%d points are inside the circumcircle of a triangle", n)
+	}
+}
+
+// countNotDelaunay returns the number of points of the triangles inside the
+// circumcircle of one of them.
+func countNotDelaunay(triangles []*Triangle) int {
+	points := ToIndexedMesh(triangles, false).Points
+	count := 0
+	for i := 0; i < len(triangles); i++ {
+		tr := triangles[i]
+		for j := 0; j < len(points); j++ {
+			if !tr.containsPoint(points[j]) && incircleSign(tr.points[0], tr.points[1], tr.points[2], points[j]) > 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestRefineSteinerLimit(t *testing.T) {
+	tcx := refinedPolygon(t)
+	err := tcx.Refine(RefineOptions{MaxArea: 0.01, MaxSteinerPoints: 50})
+	if e, ok := err.(*SteinerLimitError); !ok || e.Limit != 50 {
+		t.Fatalf("got %T %v, want *SteinerLimitError", err, err)
+	}
+	checkMesh(t, tcx.GetTriangles())
+}
+
+func TestRefineOptionErrors(t *testing.T) {
+	tcx := refinedPolygon(t)
+	count := len(tcx.GetTriangles())
+	cases := []RefineOptions{
+		{MinAngle: 40},
+		{MinAngle: -1},
+		{MinAngle: math.NaN()},
+		{MaxArea: -1},
+		{MinAngle: 20, MaxSteinerPoints: -1},
+	}
+	for i := 0; i < len(cases); i++ {
+		if _, ok := tcx.Refine(cases[i]).(*OptionError); !ok {
+			t.Errorf("%+v: the options were not rejected", cases[i])
+		}
+	}
+	if len(tcx.GetTriangles()) != count {
+		t.Error("the mesh changed")
+	}
+	// The largest angle allowed terminates
+	tcx = &SweepContext{}
+	tcx.Init(rectangle(0, 0, 10, 10))
+	tcx.AddHole(rectangle(3, 4, 6, 5))
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.Refine(RefineOptions{MinAngle: MaxMinAngle, MaxSteinerPoints: 100000}); err != nil {
+		t.Fatal(err)
+	}
+}
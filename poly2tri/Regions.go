@@ -0,0 +1,66 @@
+package poly2tri
+
+// ringWindings returns, for the sides of the mesh along the rings, how the winding number
+// changes from the right of the side p-q to its left. A counterclockwise ring adds one
+// inside.
+func (this *SweepContext) ringWindings() map[[2]*Point]int {
+	around := make(map[*Point]*Triangle)
+	for i := 0; i < len(this.maps); i++ {
+		for j := 0; j < 3; j++ {
+			around[this.maps[i].points[j]] = this.maps[i]
+		}
+	}
+	windings := make(map[[2]*Point]int)
+	for i := 0; i < len(this.rings); i++ {
+		ring := this.rings[i]
+		for j := 0; j < len(ring); j++ {
+			chain := this.edgeChain(ring[j], ring[(j+1)%len(ring)], around)
+			for k := 1; k < len(chain); k++ {
+				windings[[2]*Point{chain[k-1], chain[k]}]++
+				windings[[2]*Point{chain[k], chain[k-1]}]--
+			}
+		}
+	}
+	return windings
+}
+
+// edgeChain returns the points of the sides of the mesh along the edge a-b, from a to b.
+// around gives a triangle with each point.
+func (this *SweepContext) edgeChain(a, b *Point, around map[*Point]*Triangle) []*Point {
+	chain := []*Point{a}
+	for p := a; p != b; {
+		var next *Point
+		star := pointStar(around[p], p)
+		for i := 0; i < 2*len(star) && next == nil; i++ {
+			x := star[i/2].pointCCW(p)
+			if i%2 == 1 {
+				x = star[i/2].pointCW(p)
+			}
+			if x == b || (this.predicates.orientation(p, x, b) == 0 && (x.x-p.x)*(b.x-p.x)+(x.y-p.y)*(b.y-p.y) > 0) {
+				next = x
+			}
+		}
+		if next == nil {
+			panic(&InternalError{Op: "SweepContext.edgeChain()", Points: []*Point{a, b}})
+		}
+		p = next
+		chain = append(chain, p)
+	}
+	return chain
+}
+
+// pointStar returns the triangles around p, t being one of them.
+func pointStar(t *Triangle, p *Point) []*Triangle {
+	star := []*Triangle{t}
+	n := t.neighborCW(p)
+	for ; n != nil && n != t; n = n.neighborCW(p) {
+		star = append(star, n)
+	}
+	if n == nil {
+		// The star is open, go the other way too
+		for n = t.neighborCCW(p); n != nil; n = n.neighborCCW(p) {
+			star = append(star, n)
+		}
+	}
+	return star
+}
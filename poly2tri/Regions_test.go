@@ -0,0 +1,50 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegions(t *testing.T) {
+	cases := []struct {
+		name  string
+		rings [][]*Point
+		rule  FillRule
+		areas []float64
+	}{
+		{"island", [][]*Point{rectangle(0, 0, 30, 30), rectangle(10, 10, 20, 20), rectangle(12, 12, 18, 18)}, FillEvenOdd, []float64{800, 36}},
+		{"shared edge", [][]*Point{rectangle(0, 0, 10, 10), rectangle(10, 0, 20, 10)}, FillNonZero, []float64{100, 100}},
+		{"overlap", [][]*Point{rectangle(0, 0, 10, 10), rectangle(5, 0, 15, 10)}, FillNonZero, []float64{50, 50, 50}},
+	}
+	for i := 0; i < len(cases); i++ {
+		c := cases[i]
+		tcx := &SweepContext{}
+		tcx.InitRings(c.rings, c.rule)
+		if err := tcx.TriangulateE(); err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		regions := tcx.GetRegions()
+		if len(regions) != len(c.areas) {
+			t.Errorf("%s: got %d regions, want %d", c.name, len(regions), len(c.areas))
+			continue
+		}
+		// The regions come in the order of the mesh, so match the areas in any order
+		areas := []float64{}
+		for j := 0; j < len(regions); j++ {
+			areas = append(areas, meshArea(regions[j]))
+		}
+		for j := 0; j < len(c.areas); j++ {
+			found := false
+			for k := 0; k < len(areas) && !found; k++ {
+				if math.Abs(areas[k]-c.areas[j]) < 1e-9 {
+					areas = append(areas[:k], areas[k+1:]...)
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s: no region of area %v in %v", c.name, c.areas[j], areas)
+			}
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package poly2tri
+
+import "math"
+
+// RemoveVertex removes the vertex of the mesh at p and triangulates the polygon around it
+// again, giving the same constrained Delaunay triangulation as if it had never been
+// there. A vertex in the middle of a straight constrained edge can be removed too, the
+// two halves of the edge becoming one again, and so can the vertices of a hole removed
+// with RemoveHole. The vertex is removed from the input, so triangulating again leaves
+// it out.
+//
+// The error is an *InternalError when there is no vertex at p or when constrained edges
+// end at it, like at the corners of the contour and the holes.
+func (this *SweepContext) RemoveVertex(p *Point) (err error) {
+	if len(this.triangles) == 0 {
+		return &InternalError{Op: "SweepContext.RemoveVertex() (not triangulated)"}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = this.recoverError(r)
+		}
+		this.collectTriangles()
+	}()
+	v, t := this.vertexAt(p)
+	if v == nil || v == this.head || v == this.tail {
+		return &InternalError{Op: "SweepContext.RemoveVertex() (no such vertex)", Points: []*Point{p}, Indices: this.indicesOf([]*Point{p})}
+	}
+	// Go back to the first triangle counterclockwise around v, where the star is open if it is
+	for start, n := t, t.neighborCCW(v); n != nil && n != start; n = t.neighborCCW(v) {
+		t = n
+	}
+	open := t.neighborCCW(v) == nil
+	constrained := !open || t.getConstrainedEdgeCCW(v)
+	removed := make(map[*Triangle]bool)
+	sides := []*fanSide{}
+	// splits holds the position in sides of the edges from v that cut the polygon in two
+	splits := []int{}
+	if open {
+		splits = append(splits, 0)
+	}
+	for ; t != nil && !removed[t]; t = t.neighborCW(v) {
+		removed[t] = true
+		sides = append(sides, &fanSide{t.pointCCW(v), t.pointCW(v), t.neighborAcross(v), t.getConstrainedEdgeAcross(v), t.interior, t.region, t.attribute})
+		if t.getConstrainedEdgeCW(v) && t.neighborCW(v) != nil {
+			splits = append(splits, len(sides))
+		}
+	}
+	if open {
+		splits = append(splits, len(sides))
+	}
+	if len(splits) != 0 && len(splits) != 2 {
+		return &InternalError{Op: "SweepContext.RemoveVertex() (constrained edges end at the vertex)", Points: []*Point{v}, Indices: this.indicesOf([]*Point{v})}
+	}
+	if len(splits) == 2 {
+		// Turn the polygon so that it is cut between its last and first sides
+		sides = append(sides[splits[0]:], sides[:splits[0]]...)
+		splits[1] -= splits[0]
+		splits[0] = 0
+		if !this.straightThrough(v, sides[:splits[1]], sides[splits[1]:]) {
+			return &InternalError{Op: "SweepContext.RemoveVertex() (constrained edges end at the vertex)", Points: []*Point{v}, Indices: this.indicesOf([]*Point{v})}
+		}
+	}
+	maps := []*Triangle{}
+	for i := 0; i < len(this.maps); i++ {
+		if !removed[this.maps[i]] {
+			maps = append(maps, this.maps[i])
+		}
+	}
+	this.maps = maps
+	var triangles []*Triangle
+	if len(splits) == 0 {
+		triangles = this.fillPolygon(sides)
+	} else {
+		// Close each half with the edge between the ends of the two halves of the constraint
+		first, second := sides[:splits[1]], sides[splits[1]:]
+		a, b := first[0].x, first[len(first)-1].y
+		label := this.side_labels[[2]*Point{a, v}]
+		this.setSideLabel(a, v, 0)
+		this.setSideLabel(v, b, 0)
+		this.setSideLabel(a, b, label)
+		triangles = this.fillPolygon(append(append([]*fanSide{}, first...), &fanSide{b, a, nil, constrained, first[0].interior, first[0].region, first[0].attribute}))
+		if len(second) > 0 {
+			var across *Triangle
+			for i := 0; i < len(triangles) && across == nil; i++ {
+				if triangles[i].edgeIndex(a, b) >= 0 {
+					across = triangles[i]
+				}
+			}
+			triangles = append(triangles, this.fillPolygon(append(second, &fanSide{a, b, across, constrained, second[0].interior, second[0].region, second[0].attribute}))...)
+		}
+	}
+	this.legalizeTriangles(triangles)
+	this.removeInput(v)
+	return nil
+}
+
+// RemoveHole removes the first hole, in the order they were added, with a vertex at p:
+// its edges are no longer constrained, the area inside it is filled according to the
+// fill rule, and the mesh is made Delaunay again around it. Its vertices stay in the
+// mesh as plain vertices that RemoveVertex can remove. The other rings of AddContour and
+// InitRings, but the first one, can be removed the same way. The ring is removed from the
+// input, so triangulating again leaves it out, and the rings after it move down by one
+// for SetRingAttribute.
+//
+// The error is an *InternalError when there is no such hole.
+func (this *SweepContext) RemoveHole(p *Point) (err error) {
+	if len(this.triangles) == 0 {
+		return &InternalError{Op: "SweepContext.RemoveHole() (not triangulated)"}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = this.recoverError(r)
+		}
+		this.collectTriangles()
+	}()
+	v, _ := this.vertexAt(p)
+	r := -1
+	for i := 1; i < len(this.rings) && r < 0 && v != nil; i++ {
+		for j := 0; j < len(this.rings[i]); j++ {
+			if this.rings[i][j] == v {
+				r = i
+				break
+			}
+		}
+	}
+	if r < 0 {
+		return &InternalError{Op: "SweepContext.RemoveHole() (no such hole)", Points: []*Point{p}, Indices: this.indicesOf([]*Point{p})}
+	}
+	// The edges of the hole shared with another ring stay constrained
+	kept := make(map[[2]*Point]bool)
+	for i := 0; i < len(this.rings); i++ {
+		ring := this.rings[i]
+		for j := 0; j < len(ring) && i != r; j++ {
+			a, b := ring[j], ring[(j+1)%len(ring)]
+			kept[[2]*Point{a, b}], kept[[2]*Point{b, a}] = true, true
+		}
+	}
+	around := make(map[*Point]*Triangle)
+	for i := 0; i < len(this.maps); i++ {
+		for j := 0; j < 3; j++ {
+			around[this.maps[i].points[j]] = this.maps[i]
+		}
+	}
+	ring := this.rings[r]
+	freed := make(map[[2]*Point]bool)
+	touched := []*Triangle{}
+	for j := 0; j < len(ring); j++ {
+		a, b := ring[j], ring[(j+1)%len(ring)]
+		if kept[[2]*Point{a, b}] {
+			continue
+		}
+		freed[[2]*Point{a, b}], freed[[2]*Point{b, a}] = true, true
+		chain := this.edgeChain(a, b, around)
+		for k := 1; k < len(chain); k++ {
+			t := sideTriangle(around[chain[k-1]], chain[k-1], chain[k])
+			if t == nil {
+				t = sideTriangle(around[chain[k]], chain[k], chain[k-1])
+			}
+			i := t.edgeIndex(chain[k-1], chain[k])
+			t.constrained_edge[i] = false
+			if ot := t.neighbors[i]; ot != nil {
+				ot.constrained_edge[ot.edgeIndex(chain[k-1], chain[k])] = false
+				touched = append(touched, ot)
+			}
+			touched = append(touched, t)
+		}
+	}
+	this.removeRing(r, freed)
+	// Fill again from a triangle with a super point, outside of every ring
+	interior := make(map[*Triangle]bool)
+	var outside *Triangle
+	for i := 0; i < len(this.maps); i++ {
+		interior[this.maps[i]] = this.maps[i].interior
+		if this.maps[i].containsPoint(this.head) {
+			outside = this.maps[i]
+		}
+	}
+	this.triangles = []*Triangle{}
+	this.meshClean(outside)
+	for i := 0; i < len(this.maps); i++ {
+		if this.maps[i].interior != interior[this.maps[i]] {
+			touched = append(touched, this.maps[i])
+		}
+	}
+	this.legalizeTriangles(touched)
+	return nil
+}
+
+// removeRing drops the ring r from the input, with its edges in freed.
+func (this *SweepContext) removeRing(r int, freed map[[2]*Point]bool) {
+	this.rings = append(this.rings[:r:r], this.rings[r+1:]...)
+	edges := []*Edge{}
+	this.point_edges = make(map[*Point][]*Edge)
+	for i := 0; i < len(this.edge_list); i++ {
+		if e := this.edge_list[i]; !freed[[2]*Point{e.p, e.q}] {
+			edges = append(edges, e)
+			this.point_edges[e.q] = append(this.point_edges[e.q], e)
+		}
+	}
+	this.edge_list = edges
+	attributes := make(map[int]int)
+	for i, attribute := range this.attributes {
+		if i > r {
+			attributes[i-1] = attribute
+		} else if i < r {
+			attributes[i] = attribute
+		}
+	}
+	this.attributes = attributes
+}
+
+// straightThrough tells if the edges from v to the ends a and b of the chain first make
+// one straight edge a-b, up to the rounding of the points that split constrained edges,
+// with first on its left and second on its right.
+func (this *SweepContext) straightThrough(v *Point, first, second []*fanSide) bool {
+	a, b := first[0].x, first[len(first)-1].y
+	length := (b.x-a.x)*(b.x-a.x) + (b.y-a.y)*(b.y-a.y)
+	if math.Abs(product(a, v, b)) > 1e-9*length || !encroaches(a, b, v) {
+		return false
+	}
+	for i := 1; i < len(first); i++ {
+		if this.predicates.orientation(a, b, first[i].x) >= 0 {
+			return false
+		}
+	}
+	for i := 1; i < len(second); i++ {
+		if this.predicates.orientation(a, b, second[i].x) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// fillPolygon triangulates the counterclockwise polygon made of sides, cutting ears, and
+// links the new triangles to the mesh beyond the sides. It takes over the sides slice.
+func (this *SweepContext) fillPolygon(sides []*fanSide) []*Triangle {
+	triangles := []*Triangle{}
+	for len(sides) >= 3 {
+		n := len(sides)
+		k := -1
+		for i := 0; i < n && k < 0; i++ {
+			a, b, c := sides[i].x, sides[i].y, sides[(i+1)%n].y
+			if this.predicates.orientation(a, b, c) <= 0 && n > 3 {
+				continue
+			}
+			k = i
+			for j := 0; j < n && n > 3; j++ {
+				if d := sides[j].x; d != a && d != b && d != c && this.predicates.pointInsideTriangle(a, b, c, d) {
+					k = -1
+					break
+				}
+			}
+		}
+		if k < 0 {
+			panic(&InternalError{Op: "SweepContext.fillPolygon()", Points: []*Point{sides[0].x}})
+		}
+		s1, s2 := sides[k], sides[(k+1)%n]
+		t := NewTriangle(s1.x, s1.y, s2.y)
+		t.interior = s1.interior
+		t.region = s1.region
+		t.attribute = s1.attribute
+		this.maps = append(this.maps, t)
+		triangles = append(triangles, t)
+		edges := []*fanSide{s1, s2}
+		if n == 3 {
+			edges = append(edges, sides[(k+2)%n])
+		}
+		for i := 0; i < len(edges); i++ {
+			if edges[i].neighbor != nil {
+				t.markNeighbor(edges[i].neighbor)
+			}
+			if edges[i].constrained {
+				t.markConstrainedEdgeByPoints(edges[i].x, edges[i].y)
+			}
+		}
+		if n == 3 {
+			break
+		}
+		side := &fanSide{s1.x, s2.y, t, false, s1.interior, s1.region, s1.attribute}
+		if k == n-1 {
+			sides = append(sides[1:n-1], side)
+		} else {
+			sides = append(append(sides[:k], side), sides[k+2:]...)
+		}
+	}
+	return triangles
+}
+
+// removeInput drops the removed vertex v from the input. Two input edges meeting at v
+// become one.
+func (this *SweepContext) removeInput(v *Point) {
+	points := []*Point{}
+	for i := 0; i < len(this.points); i++ {
+		if this.points[i] != v {
+			points = append(points, this.points[i])
+		}
+	}
+	this.points = points
+	for i := 0; i < len(this.rings); i++ {
+		ring := []*Point{}
+		for j := 0; j < len(this.rings[i]); j++ {
+			if this.rings[i][j] != v {
+				ring = append(ring, this.rings[i][j])
+			}
+		}
+		this.rings[i] = ring
+	}
+	edges := []*Edge{}
+	ends := []*Point{}
+	for i := 0; i < len(this.edge_list); i++ {
+		e := this.edge_list[i]
+		if e.p == v {
+			ends = append(ends, e.q)
+		} else if e.q == v {
+			ends = append(ends, e.p)
+		} else {
+			edges = append(edges, e)
+		}
+	}
+	if len(ends) == 2 {
+		edges = append(edges, NewEdge(ends[0], ends[1]))
+	}
+	this.edge_list = edges
+	this.point_edges = make(map[*Point][]*Edge)
+	for i := 0; i < len(edges); i++ {
+		this.point_edges[edges[i].q] = append(this.point_edges[edges[i].q], edges[i])
+	}
+}
@@ -0,0 +1,107 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRemoveVertex(t *testing.T) {
+	contour, hole, points := insertionPolygon(2)
+	tcx := triangulated(t, contour, hole, points)
+	for i := 0; i < len(points); i += 2 {
+		if err := tcx.RemoveVertex(points[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kept := []*Point{}
+	for i := 1; i < len(points); i += 2 {
+		kept = append(kept, points[i])
+	}
+	checkMesh(t, tcx.GetTriangles())
+	sameMesh(t, tcx.GetTriangles(), triangulated(t, contour, hole, kept).GetTriangles())
+}
+
+func TestRemoveVertexOnEdge(t *testing.T) {
+	contour, hole, points := insertionPolygon(3)
+	tcx := triangulated(t, contour, hole, points)
+	edge := NewPoint64(75, 0.5)
+	if err := tcx.InsertPoint(edge); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.RemoveVertex(edge); err != nil {
+		t.Fatal(err)
+	}
+	sameMesh(t, tcx.GetTriangles(), triangulated(t, contour, hole, points).GetTriangles())
+	if err := tcx.RemoveVertex(contour[1]); err == nil {
+		t.Error("a corner of the contour was removed")
+	}
+}
+
+func TestRemoveHole(t *testing.T) {
+	contour, hole, points := insertionPolygon(5)
+	tcx := triangulated(t, contour, hole, points)
+	as := &AStar{}
+	tcx.AttachAStar(as)
+	if err := tcx.RemoveVertex(hole[1]); err == nil {
+		t.Error("a corner of a hole was removed")
+	}
+	if err := tcx.RemoveHole(contour[2]); err == nil {
+		t.Error("the contour was removed")
+	}
+	if err := tcx.RemoveHole(NewPoint64(hole[2].x, hole[2].y)); err != nil {
+		t.Fatal(err)
+	}
+	checkMesh(t, tcx.GetTriangles())
+	sameGraph(t, as, tcx.GetTriangles())
+	// The vertices of the hole are plain points now
+	fresh := &SweepContext{}
+	fresh.Init(contour)
+	fresh.AddPoints(append(append([]*Point{}, points...), hole...))
+	if err := fresh.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	fresh.legalizeAll()
+	sameMesh(t, tcx.GetTriangles(), fresh.GetTriangles())
+	for i := 0; i < len(hole); i++ {
+		if err := tcx.RemoveVertex(hole[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkMesh(t, tcx.GetTriangles())
+	fresh = &SweepContext{}
+	fresh.Init(contour)
+	fresh.AddPoints(points)
+	if err := fresh.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	fresh.legalizeAll()
+	sameMesh(t, tcx.GetTriangles(), fresh.GetTriangles())
+	// Triangulating again leaves the hole out
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	tcx.legalizeAll()
+	sameMesh(t, tcx.GetTriangles(), fresh.GetTriangles())
+}
+
+func TestRemoveRefinedHole(t *testing.T) {
+	tcx := &SweepContext{}
+	tcx.Init(rectangle(0, 0, 10, 10))
+	tcx.AddHole(rectangle(4, 4, 6, 6))
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.Refine(RefineOptions{MinAngle: 25, MaxArea: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.RemoveHole(NewPoint64(4, 4)); err != nil {
+		t.Fatal(err)
+	}
+	checkMesh(t, tcx.GetTriangles())
+	if area := meshArea(tcx.GetTriangles()); math.Abs(area-100) > 1e-9 {
+		t.Errorf("got area %v, want 100", area)
+	}
+	if n := countNotDelaunay(tcx.GetTriangles()); n != 0 {
+		t.Errorf("%d points inside circumcircles", n)
+	}
+}
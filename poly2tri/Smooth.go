@@ -0,0 +1,177 @@
+package poly2tri
+
+// SmoothOptions sets how SweepContext.Smooth moves the points.
+type SmoothOptions struct {
+	// Iterations is the number of passes over the points, 1 if 0.
+	Iterations int
+	// Lloyd moves each point to the centroid of its Voronoi cell, the iterations making a
+	// centroidal Voronoi tessellation, instead of the mean of its neighbors (Laplacian).
+	Lloyd bool
+}
+
+// Smooth moves the points inside the triangulated area, like the ones of Refine and the
+// Steiner points of AddPoint and InsertPoint, to give the triangles better shapes, and
+// flips edges after each move to keep the mesh Delaunay. The points of the contour and
+// the holes, the ends of the constrained edges and the points on them stay in place, so
+// the contour, the holes and the constrained edges keep their shape. A move that would
+// fold a triangle over is skipped. The values of the moved points are interpolated again
+// at their new place. The input points that move, like the ones of AddPoint and
+// InsertPoint, are replaced by copies in the mesh and in the input, so the points of the
+// caller stay where they are while triangulating again keeps the new places.
+func (this *SweepContext) Smooth(opts SmoothOptions) (err error) {
+	if len(this.triangles) == 0 {
+		return &InternalError{Op: "SweepContext.Smooth() (not triangulated)"}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = this.recoverError(r)
+		}
+		this.collectTriangles()
+	}()
+	this.legalizeAll()
+	fixed := make(map[*Point]bool)
+	for i := 0; i < len(this.rings); i++ {
+		for j := 0; j < len(this.rings[i]); j++ {
+			fixed[this.rings[i][j]] = true
+		}
+	}
+	for i := 0; i < len(this.edge_list); i++ {
+		fixed[this.edge_list[i].p], fixed[this.edge_list[i].q] = true, true
+	}
+	// copies maps the input points that moved to their copies, and the copies to themselves
+	copies := make(map[*Point]*Point)
+	defer this.replaceInput(copies)
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+	for k := 0; k < iterations; k++ {
+		around := make(map[*Point]*Triangle)
+		points := []*Point{}
+		for i := 0; i < len(this.triangles); i++ {
+			for j := 0; j < 3; j++ {
+				p := this.triangles[i].points[j]
+				if _, ok := around[p]; !ok {
+					points = append(points, p)
+				}
+				around[p] = this.triangles[i]
+			}
+		}
+		for i := 0; i < len(points); i++ {
+			p := points[i]
+			if fixed[p] {
+				continue
+			}
+			t := around[p]
+			if !t.containsPoint(p) {
+				// The triangle was flipped away from p
+				t = this.locateInterior(p)
+			}
+			star := this.smoothStar(t, p)
+			if star == nil {
+				continue
+			}
+			this.moveInStar(p, star, smoothTarget(p, star, opts.Lloyd), copies)
+			this.legalizeTriangles(star)
+			around[p] = star[0]
+		}
+	}
+	return nil
+}
+
+// smoothStar returns the triangles around p in counterclockwise order, or nil when p must
+// stay in place: on a constrained edge or next to a triangle that is not interior.
+func (this *SweepContext) smoothStar(t *Triangle, p *Point) []*Triangle {
+	if t == nil || !t.containsPoint(p) {
+		return nil
+	}
+	star := []*Triangle{}
+	for n := t; ; {
+		if !n.interior || n.getConstrainedEdgeCW(p) {
+			return nil
+		}
+		star = append(star, n)
+		if n = n.neighborCW(p); n == nil {
+			return nil
+		}
+		if n == t {
+			return star
+		}
+	}
+}
+
+// smoothTarget returns where to move p: the mean of its neighbors, or the centroid of its
+// Voronoi cell, made of the circumcenters of the triangles around it.
+func smoothTarget(p *Point, star []*Triangle, lloyd bool) *Point {
+	if !lloyd {
+		x, y := 0.0, 0.0
+		for i := 0; i < len(star); i++ {
+			q := star[i].pointCCW(p)
+			x, y = x+q.x, y+q.y
+		}
+		return NewPoint64(x/float64(len(star)), y/float64(len(star)))
+	}
+	cell := make([]*Point, len(star))
+	for i := 0; i < len(star); i++ {
+		cell[i] = star[i].circumcenter()
+	}
+	area := ringArea(cell)
+	if area <= 0 {
+		return p
+	}
+	x, y := 0.0, 0.0
+	for i := 0; i < len(cell); i++ {
+		a, b := cell[i], cell[(i+1)%len(cell)]
+		cross := a.x*b.y - b.x*a.y
+		x += (a.x + b.x) * cross
+		y += (a.y + b.y) * cross
+	}
+	return NewPoint64(x/(6*area), y/(6*area))
+}
+
+// moveInStar moves p to target if the triangles around it stay counterclockwise, and
+// interpolates its values there. An input point is swapped for a copy first, recorded in
+// copies.
+func (this *SweepContext) moveInStar(p *Point, star []*Triangle, target *Point, copies map[*Point]*Point) {
+	var found *Triangle
+	for i := 0; i < len(star); i++ {
+		a, b := star[i].pointCCW(p), star[i].pointCW(p)
+		if this.predicates.orientation(a, b, target) <= 0 {
+			return
+		}
+		if found == nil && this.predicates.pointInsideTriangle(p, a, b, target) {
+			found = star[i]
+		}
+	}
+	if found != nil {
+		target.interpolate(found.points, found.barycentric(target))
+	}
+	if _, ok := this.indices[p]; ok && copies[p] != p {
+		c := &Point{p.x, p.y, p.data}
+		for i := 0; i < len(star); i++ {
+			star[i].points[star[i].index(p)] = c
+		}
+		copies[p], copies[c] = c, c
+		p = c
+	}
+	if target.data != nil {
+		p.data = target.data
+	}
+	p.x, p.y = target.x, target.y
+}
+
+// replaceInput puts the copies of the input points that moved in their place in the
+// input.
+func (this *SweepContext) replaceInput(copies map[*Point]*Point) {
+	if len(copies) == 0 {
+		return
+	}
+	for i := 0; i < len(this.points); i++ {
+		p := this.points[i]
+		if c, ok := copies[p]; ok && c != p {
+			this.points[i] = c
+			this.indices[c] = this.indices[p]
+			delete(this.indices, p)
+		}
+	}
+}
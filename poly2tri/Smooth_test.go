@@ -0,0 +1,101 @@
+package poly2tri
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestSmoothSteinerPoints(t *testing.T) {
+	tcx := &SweepContext{}
+	tcx.Init(rectangle(0, 0, 10, 10))
+	steiner := NewPoint64(2, 3)
+	tcx.AddPoint(steiner)
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.Smooth(SmoothOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if steiner.x != 2 || steiner.y != 3 {
+		t.Errorf("the point of the caller moved to %v", steiner)
+	}
+	// The only neighbors of the point are the corners
+	vertex, _ := tcx.vertexAt(NewPoint64(5, 5))
+	if vertex == nil || vertex == steiner {
+		t.Fatal("the mesh vertex did not move to (5, 5)")
+	}
+	if tcx.InputIndex(vertex) != 4 {
+		t.Errorf("got input index %d, want 4", tcx.InputIndex(vertex))
+	}
+	// Triangulating again keeps the new place
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := tcx.vertexAt(NewPoint64(5, 5)); v != vertex {
+		t.Error("triangulating again lost the new place")
+	}
+}
+
+func TestSmoothKeepsConstraints(t *testing.T) {
+	contour := rectangle(0, 0, 10, 10)
+	tcx := &SweepContext{}
+	tcx.Init(contour)
+	if err := tcx.TriangulateE(); err != nil {
+		t.Fatal(err)
+	}
+	a, b := NewPoint64(6, 2), NewPoint64(8, 4)
+	if err := tcx.InsertConstraint(a, b); err != nil {
+		t.Fatal(err)
+	}
+	p := NewPoint64(3, 7)
+	if err := tcx.InsertPoint(p); err != nil {
+		t.Fatal(err)
+	}
+	fixed := append([]*Point{a, b}, contour...)
+	places := make([]Point, len(fixed))
+	for i := 0; i < len(fixed); i++ {
+		places[i] = *fixed[i]
+	}
+	if err := tcx.Smooth(SmoothOptions{Iterations: 3}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(fixed); i++ {
+		if fixed[i].x != places[i].x || fixed[i].y != places[i].y {
+			t.Errorf("the point %v moved to %v", places[i], fixed[i])
+		}
+	}
+	if p.x != 3 || p.y != 7 {
+		t.Errorf("the inserted point of the caller moved to %v", p)
+	}
+	if v, _ := tcx.vertexAt(p); v != nil {
+		t.Error("the vertex of the inserted point did not move")
+	}
+	checkMesh(t, tcx.GetTriangles())
+	if area := meshArea(tcx.GetTriangles()); math.Abs(area-100) > 1e-9 {
+		t.Errorf("got area %v, want 100", area)
+	}
+}
+
+func TestSmoothSharedPoints(t *testing.T) {
+	contour, hole, steiner := concurrencyInput()
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tcx := &SweepContext{}
+			tcx.Init(contour)
+			tcx.AddHole(hole)
+			tcx.AddPoints(steiner)
+			if err := tcx.TriangulateE(); err != nil {
+				t.Error(err)
+				return
+			}
+			if err := tcx.Smooth(SmoothOptions{Iterations: 2}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,172 @@
+package poly2tri
+
+import "sort"
+
+// StripRestart is the primitive restart index between the strips of StripIndices, and
+// between the fans of FanIndices. Its uint16 form is 0xFFFF.
+const StripRestart uint32 = 0xFFFFFFFF
+
+// Strips covers the triangles of the mesh with triangle strips, walking from neighbor to
+// neighbor. Each strip is a list of indices in Points; its triangle k is made of the
+// indices k, k+1 and k+2, the first two swapped when k is odd, so all the triangles stay
+// counterclockwise.
+func (this *IndexedMesh) Strips() [][]uint32 {
+	index := make(map[*Triangle]int)
+	for i := 0; i < len(this.triangles); i++ {
+		index[this.triangles[i]] = i
+	}
+	// Start from the triangles with the fewest neighbors, like on the border, so that the
+	// strips do not leave them alone
+	order := make([]int, len(this.triangles))
+	free := make([]int, len(this.triangles))
+	for i := 0; i < len(this.triangles); i++ {
+		order[i] = i
+		for j := 0; j < 3; j++ {
+			if _, ok := index[this.triangles[i].neighbors[j]]; ok {
+				free[i]++
+			}
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return free[order[i]] < free[order[j]]
+	})
+	used := make([]bool, len(this.triangles))
+	strips := [][]uint32{}
+	for i := 0; i < len(order); i++ {
+		t := this.triangles[order[i]]
+		if used[order[i]] {
+			continue
+		}
+		// Leave the first triangle through the side giving the longest strip
+		var best []*Triangle
+		var first *Point
+		for j := 0; j < 3; j++ {
+			if walk := this.stripWalk(t, j, index, used); len(walk) > len(best) {
+				best, first = walk, t.points[j]
+			}
+		}
+		j := t.index(first)
+		strip := []uint32{this.ids[t.points[j]], this.ids[t.points[(j+1)%3]], this.ids[t.points[(j+2)%3]]}
+		a, b := t.points[(j+1)%3], t.points[(j+2)%3]
+		for k := 0; k < len(best); k++ {
+			used[index[best[k]]] = true
+			if k > 0 {
+				c := best[k].oppositePoint(best[k-1], best[k-1].points[best[k-1].edgeIndex(a, b)])
+				strip = append(strip, this.ids[c])
+				a, b = b, c
+			}
+		}
+		strips = append(strips, strip)
+	}
+	return strips
+}
+
+// stripWalk returns the triangles of the strip starting with t and leaving it through
+// the side across t.points[i].
+func (this *IndexedMesh) stripWalk(t *Triangle, i int, index map[*Triangle]int, used []bool) []*Triangle {
+	walk := []*Triangle{t}
+	seen := map[*Triangle]bool{t: true}
+	a, b := t.points[(i+1)%3], t.points[(i+2)%3]
+	for {
+		side := t.edgeIndex(a, b)
+		n := t.neighbors[side]
+		k, ok := index[n]
+		if !ok || used[k] || seen[n] {
+			return walk
+		}
+		c := n.oppositePoint(t, t.points[side])
+		walk = append(walk, n)
+		seen[n] = true
+		t, a, b = n, b, c
+	}
+}
+
+// StripIndices joins the strips in one index list, separated by StripRestart if restart,
+// or else by repeated indices making degenerate triangles.
+func (this *IndexedMesh) StripIndices(restart bool) []uint32 {
+	strips := this.Strips()
+	indices := []uint32{}
+	for i := 0; i < len(strips); i++ {
+		if i > 0 {
+			if restart {
+				indices = append(indices, StripRestart)
+			} else {
+				indices = append(indices, indices[len(indices)-1], strips[i][0])
+				// The strip has to start at an even position to keep its winding
+				if len(indices)%2 == 1 {
+					indices = append(indices, strips[i][0])
+				}
+			}
+		}
+		indices = append(indices, strips[i]...)
+	}
+	return indices
+}
+
+// StripIndices16 returns StripIndices as uint16, or false when there are too many points
+// for them.
+func (this *IndexedMesh) StripIndices16(restart bool) ([]uint16, bool) {
+	if restart {
+		return shortIndices(this.StripIndices(true), len(this.Points), 0xFFFF)
+	}
+	return shortIndices(this.StripIndices(false), len(this.Points), 1<<16)
+}
+
+// Fans covers the triangles of the mesh with triangle fans around the points shared by
+// the most triangles. Each fan is a list of indices in Points starting with its center;
+// its triangle k is made of the center and the indices k+1 and k+2, counterclockwise.
+func (this *IndexedMesh) Fans() [][]uint32 {
+	index := make(map[*Triangle]int)
+	around := make(map[*Point][]*Triangle)
+	for i := 0; i < len(this.triangles); i++ {
+		t := this.triangles[i]
+		index[t] = i
+		for j := 0; j < 3; j++ {
+			around[t.points[j]] = append(around[t.points[j]], t)
+		}
+	}
+	centers := append([]*Point{}, this.Points...)
+	sort.SliceStable(centers, func(i, j int) bool {
+		return len(around[centers[i]]) > len(around[centers[j]])
+	})
+	inside := func(t *Triangle) bool {
+		_, ok := index[t]
+		return ok
+	}
+	used := make([]bool, len(this.triangles))
+	fans := [][]uint32{}
+	for i := 0; i < len(centers); i++ {
+		c := centers[i]
+		star := around[c]
+		for j := 0; j < len(star); j++ {
+			t := star[j]
+			if used[index[t]] {
+				continue
+			}
+			// Go back to the first free triangle counterclockwise around c
+			for n := t.neighborCCW(c); inside(n) && !used[index[n]] && n != star[j]; n = t.neighborCCW(c) {
+				t = n
+			}
+			fan := []uint32{this.ids[c], this.ids[t.pointCCW(c)]}
+			for ; inside(t) && !used[index[t]]; t = t.neighborCW(c) {
+				used[index[t]] = true
+				fan = append(fan, this.ids[t.pointCW(c)])
+			}
+			fans = append(fans, fan)
+		}
+	}
+	return fans
+}
+
+// FanIndices joins the fans in one index list, separated by StripRestart.
+func (this *IndexedMesh) FanIndices() []uint32 {
+	fans := this.Fans()
+	indices := []uint32{}
+	for i := 0; i < len(fans); i++ {
+		if i > 0 {
+			indices = append(indices, StripRestart)
+		}
+		indices = append(indices, fans[i]...)
+	}
+	return indices
+}
@@ -0,0 +1,159 @@
+package poly2tri
+
+import (
+	"sort"
+	"testing"
+)
+
+// triangleKey returns the indices of a triangle turned to start with the smallest one,
+// which keeps their winding.
+func triangleKey(a, b, c uint32) [3]uint32 {
+	if b < a && b < c {
+		return [3]uint32{b, c, a}
+	}
+	if c < a && c < b {
+		return [3]uint32{c, a, b}
+	}
+	return [3]uint32{a, b, c}
+}
+
+// sortedKeys returns the keys of the triangles of indices, three indices each, in order.
+func sortedKeys(indices []uint32) [][3]uint32 {
+	keys := [][3]uint32{}
+	for i := 0; i+2 < len(indices); i += 3 {
+		keys = append(keys, triangleKey(indices[i], indices[i+1], indices[i+2]))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		return a[0] < b[0] || a[0] == b[0] && (a[1] < b[1] || a[1] == b[1] && a[2] < b[2])
+	})
+	return keys
+}
+
+// expandStrips returns the triangles of strip indices, three indices each, leaving out
+// the degenerate ones.
+func expandStrips(indices []uint32) []uint32 {
+	triangles := []uint32{}
+	start := 0
+	for i := 0; i <= len(indices); i++ {
+		if i < len(indices) && indices[i] != StripRestart {
+			continue
+		}
+		strip := indices[start:i]
+		for k := 0; k+2 < len(strip); k++ {
+			a, b, c := strip[k], strip[k+1], strip[k+2]
+			if k%2 == 1 {
+				a, b = b, a
+			}
+			if a != b && b != c && c != a {
+				triangles = append(triangles, a, b, c)
+			}
+		}
+		start = i + 1
+	}
+	return triangles
+}
+
+// expandFans returns the triangles of fan indices, three indices each.
+func expandFans(indices []uint32) []uint32 {
+	triangles := []uint32{}
+	start := 0
+	for i := 0; i <= len(indices); i++ {
+		if i < len(indices) && indices[i] != StripRestart {
+			continue
+		}
+		fan := indices[start:i]
+		for k := 1; k+1 < len(fan); k++ {
+			triangles = append(triangles, fan[0], fan[k], fan[k+1])
+		}
+		start = i + 1
+	}
+	return triangles
+}
+
+// sameTriangles fails t when the triangles of got are not the ones of want with the same
+// winding, each once.
+func sameTriangles(t *testing.T, name string, got, want []uint32) {
+	t.Helper()
+	a, b := sortedKeys(got), sortedKeys(want)
+	if len(a) != len(b) {
+		t.Fatalf("%s: got %d triangles, want %d", name, len(a), len(b))
+	}
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			t.Fatalf("%s: got triangle %v, want %v", name, a[i], b[i])
+		}
+	}
+}
+
+// sameShort fails t when short is not indices as uint16, StripRestart turned to 0xFFFF.
+func sameShort(t *testing.T, name string, short []uint16, ok bool, indices []uint32) {
+	t.Helper()
+	if !ok || len(short) != len(indices) {
+		t.Fatalf("%s: got %d short indices and %v, want %d", name, len(short), ok, len(indices))
+	}
+	for i := 0; i < len(short); i++ {
+		if want := uint16(indices[i]); short[i] != want {
+			t.Fatalf("%s: short index %d is %d, want %d", name, i, short[i], want)
+		}
+	}
+}
+
+func TestStripsAndFans(t *testing.T) {
+	contour, hole, points := insertionPolygon(6)
+	tcx := triangulated(t, contour, hole, points)
+	if err := tcx.Refine(RefineOptions{MinAngle: 25, MaxArea: 40}); err != nil {
+		t.Fatal(err)
+	}
+	meshes := map[string]*IndexedMesh{
+		"polygon": ToIndexedMesh(tcx.GetTriangles(), false),
+		"band":    ToIndexedMesh(band(9), false),
+	}
+	for name, mesh := range meshes {
+		strips := mesh.Strips()
+		all := []uint32{}
+		for i := 0; i < len(strips); i++ {
+			all = append(all, expandStrips(strips[i])...)
+		}
+		sameTriangles(t, name+" strips", all, mesh.Indices)
+		restart := mesh.StripIndices(true)
+		sameTriangles(t, name+" strip indices with restart", expandStrips(restart), mesh.Indices)
+		joined := mesh.StripIndices(false)
+		for i := 0; i < len(joined); i++ {
+			if joined[i] == StripRestart {
+				t.Fatalf("%s: the strip indices without restart hold a restart", name)
+			}
+		}
+		sameTriangles(t, name+" strip indices", expandStrips(joined), mesh.Indices)
+		short, ok := mesh.StripIndices16(true)
+		sameShort(t, name+" strip indices with restart", short, ok, restart)
+		short, ok = mesh.StripIndices16(false)
+		sameShort(t, name+" strip indices", short, ok, joined)
+		fans := mesh.Fans()
+		all = []uint32{}
+		for i := 0; i < len(fans); i++ {
+			all = append(all, expandFans(fans[i])...)
+		}
+		sameTriangles(t, name+" fans", all, mesh.Indices)
+		sameTriangles(t, name+" fan indices", expandFans(mesh.FanIndices()), mesh.Indices)
+	}
+	// The strips join the triangles of the band
+	if strips := meshes["band"].Strips(); len(strips) > 2 {
+		t.Errorf("got strips %v for the band, want at most two", strips)
+	}
+}
+
+func TestStripIndices16Overflow(t *testing.T) {
+	// 0xFFFF is the restart index, so it cannot be a point with restart
+	mesh := ToIndexedMesh(band(1<<16-2), false)
+	if _, ok := mesh.StripIndices16(false); !ok {
+		t.Errorf("the strip indices of %d points do not fit in 16 bits", len(mesh.Points))
+	}
+	if short, ok := mesh.StripIndices16(true); ok || short != nil {
+		t.Errorf("the strip indices of %d points fit in 16 bits with restart", len(mesh.Points))
+	}
+	mesh = ToIndexedMesh(band(1<<16-3), false)
+	if _, ok := mesh.StripIndices16(true); !ok {
+		t.Errorf("the strip indices of %d points do not fit in 16 bits with restart", len(mesh.Points))
+	}
+}
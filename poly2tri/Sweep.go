@@ -3,22 +3,31 @@ package poly2tri
 import "math"
 
 const (
-	EPSILON   float32 = 1e-12
+	EPSILON   float64 = 1e-12
 	CW        int     = 1
 	CCW       int     = -1
 	COLLINEAR int     = 0
 )
 
 type Sweep struct {
+	predicates Predicates
 }
 
 func (this *Sweep) triangulate(tcx *SweepContext) {
+	this.sweep(tcx)
+	// Clean up
+	this.finalizationPolygon(tcx)
+}
+
+// sweep builds the mesh of the points of tcx between the head and tail points, leaving
+// the triangles outside of the edges for the finalization.
+func (this *Sweep) sweep(tcx *SweepContext) {
+	this.predicates = tcx.predicates
+	tcx.Reset()
 	tcx.initTriangulation()
 	tcx.createAdvancingFront()
 	// Sweep points; build mesh
 	this.sweepPoints(tcx)
-	// Clean up
-	this.finalizationPolygon(tcx)
 }
 
 func (this *Sweep) sweepPoints(tcx *SweepContext) {
@@ -26,7 +35,7 @@ func (this *Sweep) sweepPoints(tcx *SweepContext) {
 	for i := 1; i < length; i++ {
 		point := tcx.points[i]
 		node := this.pointEvent(tcx, point)
-		if edges := point.p2t_edge_list; edges != nil {
+		if edges := tcx.point_edges[point]; edges != nil {
 			for j := 0; j < len(edges); j++ {
 				this.edgeEventByEdge(tcx, edges[j], node)
 			}
@@ -35,14 +44,8 @@ func (this *Sweep) sweepPoints(tcx *SweepContext) {
 }
 
 func (this *Sweep) finalizationPolygon(tcx *SweepContext) {
-	// Get an Internal triangle to start with
-	t := tcx.front.head.next.triangle
-	p := tcx.front.head.next.point
-	for !t.getConstrainedEdgeCW(p) {
-		t = t.neighborCCW(p)
-	}
-	// Collect interior triangles constrained by edges
-	tcx.meshClean(t)
+	// The triangle of the first node has a super point, outside of every ring
+	tcx.meshClean(tcx.front.head.triangle)
 }
 
 func (this *Sweep) pointEvent(tcx *SweepContext, point *Point) *Node {
@@ -74,22 +77,25 @@ func (this *Sweep) edgeEventByEdge(tcx *SweepContext, edge *Edge, node *Node) {
 }
 
 func (this *Sweep) edgeEventByPoints(tcx *SweepContext, ep *Point, eq *Point, triangle *Triangle, point *Point) {
+	if triangle == nil {
+		panic(&FlipFailedError{Points: []*Point{ep, eq, point}})
+	}
 	if this.isEdgeSideOfTriangle(triangle, ep, eq) {
 		return
 	}
 	p1 := triangle.pointCCW(point)
 	o1 := this.orient2d(eq, p1, ep)
 	if o1 == COLLINEAR {
-		// TODO integrate here changes from C++ version
-		// (C++ repo revision 09880a869095 dated March 8, 2011)
-		panic("poly2tri EdgeEvent: Collinear not supported! " + eq.toString() + " " + p1.toString() + " " + ep.toString())
+		// Changes from C++ version (C++ repo revision 09880a869095 dated March 8, 2011)
+		this.splitEdgeEvent(tcx, ep, eq, triangle, point, p1)
+		return
 	}
 	p2 := triangle.pointCW(point)
 	o2 := this.orient2d(eq, p2, ep)
 	if o2 == COLLINEAR {
-		// TODO integrate here changes from C++ version
-		// (C++ repo revision 09880a869095 dated March 8, 2011)
-		panic("poly2tri EdgeEvent: Collinear not supported! " + eq.toString() + " " + p2.toString() + " " + ep.toString())
+		// Changes from C++ version (C++ repo revision 09880a869095 dated March 8, 2011)
+		this.splitEdgeEvent(tcx, ep, eq, triangle, point, p2)
+		return
 	}
 	if o1 == o2 {
 		// Need to decide if we are rotating CW or CCW to get to a triangle
@@ -106,6 +112,27 @@ func (this *Sweep) edgeEventByPoints(tcx *SweepContext, ep *Point, eq *Point, tr
 	}
 }
 
+/**
+ * The constrained edge ep-eq goes through p, a point of triangle next to eq.
+ * eq-p is already a side of the triangle so we mark it and carry on with ep-p.
+ * We don't change the given constraint but keep a new edge for the rest of the event.
+ */
+func (this *Sweep) splitEdgeEvent(tcx *SweepContext, ep *Point, eq *Point, triangle *Triangle, point *Point, p *Point) {
+	c := tcx.edge_event.constrained_edge
+	if c.p != ep || c.q != eq || !triangle.containsPoints(eq, p) || !this.isBetween(eq, p, ep) {
+		// Only the constraint itself can be split, not the intermediate flip edges
+		panic(&CollinearEdgeError{Points: []*Point{eq, p, ep}})
+	}
+	this.isEdgeSideOfTriangle(triangle, p, eq)
+	tcx.edge_event.constrained_edge = &Edge{p: ep, q: p}
+	this.edgeEventByPoints(tcx, ep, p, triangle.neighborAcross(point), p)
+}
+
+// isBetween tells if p, known to be collinear with a and b, lies strictly between them.
+func (this *Sweep) isBetween(a, p, b *Point) bool {
+	return (p.x-a.x)*(b.x-a.x)+(p.y-a.y)*(b.y-a.y) > 0 && (p.x-b.x)*(a.x-b.x)+(p.y-b.y)*(a.y-b.y) > 0
+}
+
 func (this *Sweep) isEdgeSideOfTriangle(triangle *Triangle, ep *Point, eq *Point) bool {
 	index := triangle.edgeIndex(ep, eq)
 	if index != -1 {
@@ -181,9 +208,9 @@ func (this *Sweep) isBasinAngleRight(node *Node) bool {
 	ax := node.point.x - node.next.next.point.x
 	ay := node.point.y - node.next.next.point.y
 	if ay < 0 {
-		panic("unordered y")
+		panic(&InternalError{Op: "Sweep.isBasinAngleRight() (unordered y)", Points: []*Point{node.point, node.next.next.point}})
 	}
-	return (ax >= 0 || float32(math.Abs(float64(ax))) < ay)
+	return (ax >= 0 || math.Abs(ax) < ay)
 }
 
 func (this *Sweep) legalize(tcx *SweepContext, t *Triangle) bool {
@@ -256,31 +283,7 @@ func (this *Sweep) legalize(tcx *SweepContext, t *Triangle) bool {
  * @return {boolean} true if d is inside circle, false if on circle edge
  */
 func (this *Sweep) inCircle(pa, pb, pc, pd *Point) bool {
-	adx := pa.x - pd.x
-	ady := pa.y - pd.y
-	bdx := pb.x - pd.x
-	bdy := pb.y - pd.y
-	adxbdy := adx * bdy
-	bdxady := bdx * ady
-	oabd := adxbdy - bdxady
-	if oabd <= 0 {
-		return false
-	}
-	cdx := pc.x - pd.x
-	cdy := pc.y - pd.y
-	cdxady := cdx * ady
-	adxcdy := adx * cdy
-	ocad := cdxady - adxcdy
-	if ocad <= 0 {
-		return false
-	}
-	bdxcdy := bdx * cdy
-	cdxbdy := cdx * bdy
-	alift := adx*adx + ady*ady
-	blift := bdx*bdx + bdy*bdy
-	clift := cdx*cdx + cdy*cdy
-	det := alift*(bdxcdy-cdxbdy) + blift*ocad + clift*oabd
-	return det > 0
+	return this.predicates.inCircle(pa, pb, pc, pd)
 }
 
 /**
@@ -402,7 +405,7 @@ func (this *Sweep) fillBasinReq(tcx *SweepContext, node *Node) {
 	this.fillBasinReq(tcx, node)
 }
 func (this *Sweep) isShallow(tcx *SweepContext, node *Node) bool {
-	height := float32(0)
+	height := float64(0)
 	if tcx.basin.left_highest {
 		height = tcx.basin.left_node.point.y - node.point.y
 	} else {
@@ -539,13 +542,13 @@ func (this *Sweep) fillLeftConcaveEdgeEvent(tcx *SweepContext, edge *Edge, node
 func (this *Sweep) flipEdgeEvent(tcx *SweepContext, ep *Point, eq *Point, t *Triangle, p *Point) {
 	ot := t.neighborAcross(p)
 	if ot == nil {
-		panic("FLIP failed due to missing triangle!")
+		panic(&FlipFailedError{Points: []*Point{ep, eq, p}})
 	}
 	op := ot.oppositePoint(t, p)
 	// Additional check from Java version (see issue #88)
 	if t.getConstrainedEdgeAcross(p) {
 		index := t.index(p)
-		panic("poly2tri Intersecting Constraints " + p.toString() + " " + op.toString() + " " + t.points[(index+1)%3].toString() + " " + t.points[(index+2)%3].toString())
+		panic(&IntersectingConstraintsError{Points: []*Point{p, op, t.points[(index+1)%3], t.points[(index+2)%3]}})
 	}
 	if this.inScanArea(p, t.pointCCW(p), t.pointCW(p), op) {
 		// Lets rotate shared edge one vertex CW
@@ -570,11 +573,22 @@ func (this *Sweep) flipEdgeEvent(tcx *SweepContext, ep *Point, eq *Point, t *Tri
 			}
 		} else {
 			o := this.orient2d(eq, op, ep)
+			if o == COLLINEAR {
+				// op is on the constrained edge, eq-op is now a side of t and ot
+				this.splitEdgeEvent(tcx, ep, eq, t, p, op)
+				this.legalize(tcx, t)
+				this.legalize(tcx, ot)
+				return
+			}
 			t = this.nextFlipTriangle(tcx, o, t, ot, p, op)
 			this.flipEdgeEvent(tcx, ep, eq, t, p)
 		}
 	} else {
 		newP := this.nextFlipPoint(ep, eq, ot, op)
+		if newP == nil {
+			// [Unsupported] opposing point on constrained edge outside of the scan area
+			panic(&CollinearEdgeError{Points: []*Point{eq, op, ep}})
+		}
 		this.flipScanEdgeEvent(tcx, ep, eq, t, ot, newP)
 		this.edgeEventByPoints(tcx, ep, eq, t, p)
 	}
@@ -606,49 +620,32 @@ func (this *Sweep) nextFlipPoint(ep *Point, eq *Point, ot *Triangle, op *Point)
 	} else if o2d == CCW {
 		// Left
 		return ot.pointCW(op)
-	} else {
-		panic("poly2tri [Unsupported] nextFlipPoint: opposing point on constrained edge! " + eq.toString() + " " + op.toString() + " " + ep.toString())
 	}
+	// Opposing point on constrained edge
+	return nil
 }
 
 func (this *Sweep) flipScanEdgeEvent(tcx *SweepContext, ep *Point, eq *Point, flip_triangle *Triangle, t *Triangle, p *Point) {
 	ot := t.neighborAcross(p)
 	if ot == nil {
-		panic("FLIP failed due to missing triangle")
+		panic(&FlipFailedError{Points: []*Point{ep, eq, p}})
 	}
 	op := ot.oppositePoint(t, p)
-	if this.inScanArea(eq, flip_triangle.pointCCW(eq), flip_triangle.pointCW(eq), op) {
-		// flip with new edge op.eq
+	newP := this.nextFlipPoint(ep, eq, ot, op)
+	if newP == nil || this.inScanArea(eq, flip_triangle.pointCCW(eq), flip_triangle.pointCW(eq), op) {
+		// flip with new edge op.eq, an op on the constrained edge is split off by the edge event
 		this.flipEdgeEvent(tcx, eq, op, ot, op)
 	} else {
-		newP := this.nextFlipPoint(ep, eq, ot, op)
 		this.flipScanEdgeEvent(tcx, ep, eq, flip_triangle, ot, newP)
 	}
 }
 
 func (this *Sweep) orient2d(pa, pb, pc *Point) int {
-	detleft := (pa.x - pc.x) * (pb.y - pc.y)
-	detright := (pa.y - pc.y) * (pb.x - pc.x)
-	val := detleft - detright
-	if val > -(EPSILON) && val < (EPSILON) {
-		return COLLINEAR
-	} else if val > 0 {
-		return CCW
-	} else {
-		return CW
-	}
+	return this.predicates.orient2d(pa, pb, pc)
 }
 
 func (this *Sweep) inScanArea(pa, pb, pc, pd *Point) bool {
-	oadb := (pa.x-pb.x)*(pd.y-pb.y) - (pd.x-pb.x)*(pa.y-pb.y)
-	if oadb >= -EPSILON {
-		return false
-	}
-	oadc := (pa.x-pc.x)*(pd.y-pc.y) - (pd.x-pc.x)*(pa.y-pc.y)
-	if oadc <= EPSILON {
-		return false
-	}
-	return true
+	return this.predicates.inScanArea(pa, pb, pc, pd)
 }
 
 func (this *Sweep) isAngleObtuse(pa, pb, pc *Point) bool {
@@ -1,33 +1,66 @@
 package poly2tri
 
 const (
-	kAlpha float32 = 0.3
+	kAlpha float64 = 0.3
 )
 
+// SweepContext triangulates one polygon. A context must not be used from several
+// goroutines at once, but contexts sharing the same points may run in parallel
+// since the library never writes to the points it is given.
 type SweepContext struct {
-	triangles  []*Triangle
-	maps       []*Triangle
-	points     []*Point
-	edge_list  []*Edge
-	pmin       *Point
-	pmax       *Point
-	front      *AdvancingFront
-	head       *Point
-	tail       *Point
-	af_head    *Node
-	af_middle  *Node
-	af_tail    *Node
-	basin      *Basin
-	edge_event *EdgeEvent
-	sweep      *Sweep
+	triangles   []*Triangle
+	maps        []*Triangle
+	points      []*Point
+	edge_list   []*Edge
+	point_edges map[*Point][]*Edge
+	rings       [][]*Point
+	pmin        *Point
+	pmax        *Point
+	front       *AdvancingFront
+	head        *Point
+	tail        *Point
+	af_head     *Node
+	af_middle   *Node
+	af_tail     *Node
+	basin       *Basin
+	edge_event  *EdgeEvent
+	sweep       *Sweep
+	indices     map[*Point]int
+	err         error
+	predicates  Predicates
+	astars      []*AStar
+	fill_rule   FillRule
+	attributes  map[int]int
+	seeds       []*regionSeed
+	edge_labels []*edgeLabel
+	side_labels map[[2]*Point]int
 }
 
+// Init starts a new triangulation of contour. The context keeps its own copy of
+// the slices it is given and never modifies the caller's slices or points.
 func (this *SweepContext) Init(contour []*Point) {
 	this.sweep = &Sweep{}
+	this.points = append([]*Point{}, contour...)
+	this.edge_list = []*Edge{}
+	this.point_edges = make(map[*Point][]*Edge)
+	this.rings = [][]*Point{append([]*Point{}, contour...)}
+	this.indices = make(map[*Point]int)
+	this.err = nil
+	this.fill_rule = FillEvenOdd
+	this.attributes = make(map[int]int)
+	this.seeds = nil
+	this.edge_labels = nil
+	this.side_labels = make(map[[2]*Point]int)
+	this.Reset()
+	this.addIndices(contour)
+	this.initEdges(contour)
+}
+
+// Reset drops the triangles of the previous run and keeps the contour, holes and
+// points, so the context can be triangulated again.
+func (this *SweepContext) Reset() {
 	this.triangles = []*Triangle{}
 	this.maps = []*Triangle{}
-	this.points = contour
-	this.edge_list = []*Edge{}
 	this.pmin, this.pmax = nil, nil
 	this.front = nil
 	this.head = nil
@@ -37,14 +70,26 @@ func (this *SweepContext) Init(contour []*Point) {
 	this.af_tail = nil
 	this.basin = &Basin{}
 	this.edge_event = &EdgeEvent{}
-	this.initEdges(this.points)
+}
+
+// SetPredicates selects the arithmetic of the geometric tests, RobustPredicates by default.
+func (this *SweepContext) SetPredicates(predicates Predicates) {
+	this.predicates = predicates
 }
 
 func (this *SweepContext) AddHole(polyline []*Point) {
+	this.addIndices(polyline)
 	this.initEdges(polyline)
+	this.rings = append(this.rings, append([]*Point{}, polyline...))
 	this.points = append(this.points, polyline...)
 }
 
+// AddContour adds another outer contour. The contours and holes are filled with the
+// even-odd rule, so a contour inside a hole is an island. InitRings takes other rules.
+func (this *SweepContext) AddContour(polyline []*Point) {
+	this.AddHole(polyline)
+}
+
 func (this *SweepContext) AddHoles(holes [][]*Point) {
 	length := len(holes)
 	for i := 0; i < length; i++ {
@@ -52,22 +97,78 @@ func (this *SweepContext) AddHoles(holes [][]*Point) {
 	}
 }
 
+// InputIndex returns the position of p in the input, counting the contour, then the holes
+// and points in the order they were added, or -1 for a point created by the library.
+func (this *SweepContext) InputIndex(p *Point) int {
+	return this.indicesOf([]*Point{p})[0]
+}
+
 func (this *SweepContext) AddPoint(point *Point) {
+	this.addIndices([]*Point{point})
 	this.points = append(this.points, point)
 }
 
 func (this *SweepContext) AddPoints(points []*Point) {
+	this.addIndices(points)
 	this.points = append(this.points, points...)
 }
 
+// Triangulate panics with one of the errors of TriangulateE on bad input.
 func (this *SweepContext) Triangulate() {
+	if err := this.TriangulateE(); err != nil {
+		panic(err)
+	}
+}
+
+// TriangulateE triangulates like Triangulate but returns an error instead of panicking.
+// The error is one of *IntersectingConstraintsError, *CollinearEdgeError,
+// *DuplicatePointError, *FlipFailedError or *InternalError, and its Indices give the
+// position of each offending point in the input (contour, then holes and points in the
+// order they were added, -1 for points created by the library).
+// Every call starts over from the input, so it can be called again on the same context.
+func (this *SweepContext) TriangulateE() (err error) {
+	if this.err != nil {
+		return this.err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = this.recoverError(r)
+		}
+	}()
 	this.sweep.triangulate(this)
+	for i := 0; i < len(this.astars); i++ {
+		this.astars[i].update(this.triangles)
+	}
+	return nil
+}
+
+// AttachAStar builds astar on the triangles of the context and keeps it up to date:
+// triangulating again, InsertPoint, InsertConstraint, RemoveConstraint, RemoveVertex,
+// RemoveHole, Refine and Smooth all update its graph before they return.
+func (this *SweepContext) AttachAStar(astar *AStar) {
+	astar.Init(this.triangles)
+	this.astars = append(this.astars, astar)
 }
 
 func (this *SweepContext) GetTriangles() []*Triangle {
 	return this.triangles
 }
 
+// GetRegions returns the triangles of each region, the connected parts of the filled
+// area bounded by the contours and holes, or by the rings of InitRings, indexed by
+// Triangle.GetRegion. Two filled areas that only share an edge of a ring are two regions.
+func (this *SweepContext) GetRegions() [][]*Triangle {
+	regions := [][]*Triangle{}
+	for i := 0; i < len(this.triangles); i++ {
+		t := this.triangles[i]
+		for len(regions) <= t.region {
+			regions = append(regions, []*Triangle{})
+		}
+		regions[t.region] = append(regions[t.region], t)
+	}
+	return regions
+}
+
 func (this *SweepContext) getBoundingBox() (*Point, *Point) {
 	return this.pmin, this.pmax
 }
@@ -95,12 +196,12 @@ func (this *SweepContext) initTriangulation() {
 			ymin = p.y
 		}
 	}
-	this.pmin = NewPoint(xmin, ymin)
-	this.pmax = NewPoint(xmax, ymax)
+	this.pmin = NewPoint64(xmin, ymin)
+	this.pmax = NewPoint64(xmax, ymax)
 	dx := kAlpha * (xmax - xmin)
 	dy := kAlpha * (ymax - ymin)
-	this.head = NewPoint(xmax+dx, ymin-dy)
-	this.tail = NewPoint(xmin-dx, ymin-dy)
+	this.head = NewPoint64(xmax+dx, ymin-dy)
+	this.tail = NewPoint64(xmin-dx, ymin-dy)
 	// Sort points along y-axis
 	shot := &ISort{}
 	shot.Data = this.points
@@ -112,19 +213,53 @@ func (this *SweepContext) initTriangulation() {
 		} else {
 			return _a.y-_b.y < 0
 		}
-		return false
 	}
 	shot.Sort()
 	shot.Free()
+	for i := 1; i < length; i++ {
+		if this.points[i].equals(this.points[i-1]) {
+			panic(&DuplicatePointError{Points: []*Point{this.points[i-1], this.points[i]}})
+		}
+	}
 }
 
 func (this *SweepContext) initEdges(polyline []*Point) {
 	lengh := len(polyline)
 	for i := 0; i < lengh; i++ {
-		this.edge_list = append(this.edge_list, NewEdge(polyline[i], polyline[(i+1)%lengh]))
+		p, q := polyline[i], polyline[(i+1)%lengh]
+		if p.equals(q) {
+			// Edge.Init would panic, keep the first error for TriangulateE instead
+			if this.err == nil {
+				this.err = &DuplicatePointError{Points: []*Point{p, q}, Indices: this.indicesOf([]*Point{p, q})}
+			}
+			continue
+		}
+		edge := NewEdge(p, q)
+		this.edge_list = append(this.edge_list, edge)
+		this.point_edges[edge.q] = append(this.point_edges[edge.q], edge)
+	}
+}
+
+func (this *SweepContext) addIndices(points []*Point) {
+	for i := 0; i < len(points); i++ {
+		if _, ok := this.indices[points[i]]; !ok {
+			this.indices[points[i]] = len(this.indices)
+		}
 	}
 }
 
+func (this *SweepContext) indicesOf(points []*Point) []int {
+	indices := make([]int, len(points))
+	for i := 0; i < len(points); i++ {
+		if index, ok := this.indices[points[i]]; ok {
+			indices[i] = index
+		} else {
+			indices[i] = -1
+		}
+	}
+	return indices
+}
+
 func (this *SweepContext) locateNode(point *Point) *Node {
 	return this.front.locateNode(point.x)
 }
@@ -163,19 +298,51 @@ func (this *SweepContext) removeFromMap(triangle *Triangle) {
 	}
 }
 
+// meshClean floods the mesh from triangle, which is outside of every ring, adding up the
+// winding numbers of the rings crossed on the way: the fill rule tells which triangles
+// are interior. The interior triangles are then split in regions bounded by the rings.
 func (this *SweepContext) meshClean(triangle *Triangle) {
+	windings := this.ringWindings()
+	winding := map[*Triangle]int{triangle: 0}
 	triangles := []*Triangle{triangle}
 	for len(triangles) > 0 {
 		t := triangles[0]
 		triangles = triangles[1:]
-		if !t.interior {
-			t.interior = true
+		for i := 0; i < 3; i++ {
+			n := t.neighbors[i]
+			if _, ok := winding[n]; n != nil && !ok {
+				winding[n] = winding[t] + windings[[2]*Point{t.points[(i+2)%3], t.points[(i+1)%3]}]
+				triangles = append(triangles, n)
+			}
+		}
+	}
+	for i := 0; i < len(this.maps); i++ {
+		this.maps[i].interior = this.fill_rule.contains(winding[this.maps[i]])
+	}
+	region := 0
+	seen := make(map[*Triangle]bool)
+	for i := 0; i < len(this.maps); i++ {
+		if !this.maps[i].interior || seen[this.maps[i]] {
+			continue
+		}
+		seen[this.maps[i]] = true
+		triangles = []*Triangle{this.maps[i]}
+		for len(triangles) > 0 {
+			t := triangles[0]
+			triangles = triangles[1:]
+			t.region = region
 			this.triangles = append(this.triangles, t)
-			for i := 0; i < 3; i++ {
-				if !t.constrained_edge[i] {
-					triangles = append(triangles, t.neighbors[i])
+			for j := 0; j < 3; j++ {
+				n := t.neighbors[j]
+				if _, ring := windings[[2]*Point{t.points[(j+1)%3], t.points[(j+2)%3]}]; n != nil && n.interior && !seen[n] && !ring {
+					seen[n] = true
+					triangles = append(triangles, n)
 				}
 			}
 		}
+		region++
 	}
+	this.spreadAttributes()
+	this.labelSides()
+	this.applyLabels(this.maps)
 }
@@ -0,0 +1,98 @@
+package poly2tri
+
+import (
+	"math"
+	"testing"
+)
+
+// meshArea returns the area covered by the triangles.
+func meshArea(triangles []*Triangle) float64 {
+	area := 0.0
+	for i := 0; i < len(triangles); i++ {
+		area += triangles[i].area()
+	}
+	return area
+}
+
+// checkMesh fails t when a triangle is not counterclockwise or when two neighbors do
+// not agree on their common side.
+func checkMesh(t *testing.T, triangles []*Triangle) {
+	t.Helper()
+	for i := 0; i < len(triangles); i++ {
+		tr := triangles[i]
+		if orient2dSign(tr.points[0], tr.points[1], tr.points[2]) <= 0 {
+			t.Errorf("triangle %v is not counterclockwise", tr.points)
+		}
+		for j := 0; j < 3; j++ {
+			n := tr.neighbors[j]
+			if n == nil {
+				continue
+			}
+			p, q := tr.points[(j+1)%3], tr.points[(j+2)%3]
+			k := n.edgeIndex(p, q)
+			if k < 0 || n.neighbors[k] != tr {
+				t.Errorf("triangle %v and its neighbor %v do not share %v %v", tr.points, n.points, p, q)
+			} else if n.constrained_edge[k] != tr.constrained_edge[j] {
+				t.Errorf("side %v %v is constrained on one side only", p, q)
+			}
+		}
+	}
+}
+
+func TestCollinearPoints(t *testing.T) {
+	p := NewPoint64
+	cases := []struct {
+		name    string
+		contour []*Point
+		holes   [][]*Point
+		steiner []*Point
+		area    float64
+	}{
+		{"bottom", []*Point{p(0, 0), p(5, 0), p(10, 0), p(10, 10), p(0, 10)}, nil, nil, 100},
+		{"top", []*Point{p(0, 0), p(10, 0), p(10, 10), p(5, 10), p(0, 10)}, nil, nil, 100},
+		{"sides", []*Point{p(0, 0), p(10, 0), p(10, 5), p(10, 10), p(0, 10), p(0, 5)}, nil, nil, 100},
+		{"diagonal", []*Point{p(0, 0), p(5, 5), p(10, 10), p(0, 10)}, nil, nil, 50},
+		{"reflex diagonal", []*Point{p(0, 0), p(10, 0), p(10, 10), p(5, 5)}, nil, nil, 50},
+		{"footprint", []*Point{p(0, 0), p(4, 0), p(8, 0), p(8, 4), p(8, 8), p(4, 8), p(4, 12), p(0, 12), p(0, 8), p(0, 4)}, nil, nil, 80},
+		{"hole", rectangle(0, 0, 10, 10), [][]*Point{{p(2, 2), p(2, 5), p(2, 8), p(5, 8), p(8, 8), p(8, 5), p(8, 2), p(5, 2)}}, nil, 64},
+		{"steiner points", []*Point{p(0, 0), p(1, 1), p(2, 2), p(3, 3), p(4, 4), p(10, 10), p(0, 10)}, nil, []*Point{p(1, 3), p(2, 5)}, 50},
+	}
+	for i := 0; i < len(cases); i++ {
+		c := cases[i]
+		tcx := &SweepContext{}
+		tcx.Init(c.contour)
+		for j := 0; j < len(c.holes); j++ {
+			tcx.AddHole(c.holes[j])
+		}
+		tcx.AddPoints(c.steiner)
+		if err := tcx.TriangulateE(); err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		checkMesh(t, tcx.GetTriangles())
+		if area := meshArea(tcx.GetTriangles()); math.Abs(area-c.area) > 1e-9 {
+			t.Errorf("%s: got area %v, want %v", c.name, area, c.area)
+		}
+		// The collinear points split the constrained edges
+		constrained := 0
+		triangles := tcx.GetTriangles()
+		inside := make(map[*Triangle]bool)
+		for j := 0; j < len(triangles); j++ {
+			inside[triangles[j]] = true
+		}
+		for j := 0; j < len(triangles); j++ {
+			for k := 0; k < 3; k++ {
+				if triangles[j].constrained_edge[k] && !inside[triangles[j].neighbors[k]] {
+					constrained++
+				}
+			}
+		}
+		points := len(c.contour)
+		for j := 0; j < len(c.holes); j++ {
+			points += len(c.holes[j])
+		}
+		if constrained != points {
+			t.Errorf("%s: got %d boundary sides, want %d", c.name, constrained, points)
+		}
+	}
+}
@@ -4,6 +4,9 @@ type Triangle struct {
 	points           []*Point
 	neighbors        []*Triangle
 	interior         bool
+	region           int
+	attribute        int
+	labels           []int
 	constrained_edge []bool
 	delaunay_edge    []bool
 }
@@ -18,6 +21,7 @@ func (this *Triangle) Init(a, b, c *Point) {
 	this.points = []*Point{a, b, c}
 	this.neighbors = []*Triangle{nil, nil, nil}
 	this.interior = false
+	this.labels = nil
 	this.constrained_edge = []bool{false, false, false}
 	this.delaunay_edge = []bool{false, false, false}
 }
@@ -26,6 +30,26 @@ func (this *Triangle) GetPoints() []*Point {
 	return this.points
 }
 
+// GetRegion returns the index of the region of the triangle in SweepContext.GetRegions.
+func (this *Triangle) GetRegion() int {
+	return this.region
+}
+
+// GetAttribute returns the attribute of the input ring or seed point whose area holds the
+// triangle, or 0. See SweepContext.SetRingAttribute and SweepContext.AddRegionSeed.
+func (this *Triangle) GetAttribute() int {
+	return this.attribute
+}
+
+// GetEdgeLabel returns the label of the input edge under the side across points[i], or 0.
+// See SweepContext.SetEdgeLabel.
+func (this *Triangle) GetEdgeLabel(i int) int {
+	if this.labels == nil {
+		return 0
+	}
+	return this.labels[i]
+}
+
 func (this *Triangle) containsPoint(point *Point) bool {
 	points := this.points
 	return (point == points[0] || point == points[1] || point == points[2])
@@ -49,7 +73,7 @@ func (this *Triangle) markNeighborPointers(p1 *Point, p2 *Point, t *Triangle) {
 	} else if (p1 == points[0] && p2 == points[1]) || (p1 == points[1] && p2 == points[0]) {
 		this.neighbors[2] = t
 	} else {
-		panic("poly2tri Invalid Triangle.markNeighborPointers() call")
+		panic(&InternalError{Op: "Triangle.markNeighborPointers()", Points: []*Point{p1, p2}})
 	}
 }
 func (this *Triangle) markNeighbor(t *Triangle) {
@@ -259,7 +283,7 @@ func (this *Triangle) legalize(opoint, npoint *Point) {
 		points[2] = points[1]
 		points[1] = npoint
 	} else {
-		panic("poly2tri Invalid Triangle.legalize() call")
+		panic(&InternalError{Op: "Triangle.legalize()", Points: []*Point{opoint, npoint}})
 	}
 }
 
@@ -273,8 +297,7 @@ func (this *Triangle) index(p *Point) int {
 	} else if p == points[2] {
 		return 2
 	}
-	panic("poly2tri Invalid Triangle.index() call")
-	return -1
+	panic(&InternalError{Op: "Triangle.index()", Points: []*Point{p}})
 }
 
 func (this *Triangle) edgeIndex(p1, p2 *Point) int {
@@ -318,17 +341,32 @@ func (this *Triangle) markConstrainedEdgeByPoints(p, q *Point) {
 	}
 }
 
-func (this *Triangle) pointInsideTriangle(pp *Point) bool {
-	p1 := this.points[0]
-	p2 := this.points[1]
-	p3 := this.points[2]
-	if product(p1, p2, p3) >= 0 {
-		return product(p1, p2, pp) >= 0 && product(p2, p3, pp) >= 0 && product(p3, p1, pp) >= 0
-	} else {
-		return product(p1, p2, pp) <= 0 && product(p2, p3, pp) <= 0 && product(p3, p1, pp) <= 0
-	}
+// circumcenter returns the center of the circle through the three points.
+func (this *Triangle) circumcenter() *Point {
+	a, b, c := this.points[0], this.points[1], this.points[2]
+	bx, by := b.x-a.x, b.y-a.y
+	cx, cy := c.x-a.x, c.y-a.y
+	d := 2 * (bx*cy - by*cx)
+	bl, cl := bx*bx+by*by, cx*cx+cy*cy
+	return NewPoint64(a.x+(cy*bl-by*cl)/d, a.y+(bx*cl-cx*bl)/d)
+}
+
+// barycentric returns the weights of the points of the triangle giving p.
+func (this *Triangle) barycentric(p *Point) []float64 {
+	a, b, c := this.points[0], this.points[1], this.points[2]
+	area := product(a, b, c)
+	return []float64{product(p, b, c) / area, product(a, p, c) / area, product(a, b, p) / area}
+}
+
+// area returns the area of the counterclockwise triangle.
+func (this *Triangle) area() float64 {
+	return product(this.points[0], this.points[1], this.points[2]) / 2
+}
+
+func (this *Triangle) pointInsideTriangle(pp *Point, predicates Predicates) bool {
+	return predicates.pointInsideTriangle(this.points[0], this.points[1], this.points[2], pp)
 }
 
-func product(p1, p2, p3 *Point) float32 {
+func product(p1, p2, p3 *Point) float64 {
 	return (p1.x-p3.x)*(p2.y-p3.y) - (p1.y-p3.y)*(p2.x-p3.x)
 }
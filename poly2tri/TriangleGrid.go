@@ -0,0 +1,137 @@
+package poly2tri
+
+import "math"
+
+// triangleGrid is a uniform grid over the nodes of an AStar graph, about one cell per
+// triangle, so finding the triangle at a point only tests the few triangles of its cell.
+// The nodes of each cell are sorted by their order in the graph. The grid keeps its
+// bounds and cell size when nodes are added or removed; nodes is the count it was sized for.
+type triangleGrid struct {
+	minX, minY    float64
+	size          float64
+	columns, rows int
+	cells         [][]*SpatialNode
+	nodes         int
+}
+
+func newTriangleGrid(spatials []*SpatialNode) *triangleGrid {
+	g := &triangleGrid{nodes: len(spatials)}
+	if len(spatials) == 0 {
+		return g
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i := 0; i < len(spatials); i++ {
+		tp := spatials[i].t.points
+		for j := 0; j < 3; j++ {
+			minX, minY = math.Min(minX, tp[j].x), math.Min(minY, tp[j].y)
+			maxX, maxY = math.Max(maxX, tp[j].x), math.Max(maxY, tp[j].y)
+		}
+	}
+	g.minX, g.minY = minX, minY
+	w, h := maxX-minX, maxY-minY
+	g.size = math.Sqrt(w * h / float64(len(spatials)))
+	if !(g.size > 0) {
+		g.size = math.Max(w, h)
+	}
+	if !(g.size > 0) {
+		g.size = 1
+	}
+	g.columns = int(math.Min(w/g.size, float64(len(spatials)))) + 1
+	g.rows = int(math.Min(h/g.size, float64(len(spatials)))) + 1
+	// Count the nodes of each cell, then place them in one backing array
+	counts := make([]int, g.columns*g.rows)
+	for i := 0; i < len(spatials); i++ {
+		v := spatials[i]
+		v.cells = g.cellRange(v.t)
+		for r := v.cells[1]; r <= v.cells[3]; r++ {
+			for c := v.cells[0]; c <= v.cells[2]; c++ {
+				counts[r*g.columns+c]++
+			}
+		}
+	}
+	total := 0
+	for c := 0; c < len(counts); c++ {
+		total += counts[c]
+	}
+	items := make([]*SpatialNode, total)
+	g.cells = make([][]*SpatialNode, len(counts))
+	start := 0
+	for c := 0; c < len(counts); c++ {
+		g.cells[c] = items[start : start : start+counts[c]]
+		start += counts[c]
+	}
+	for i := 0; i < len(spatials); i++ {
+		v := spatials[i]
+		for r := v.cells[1]; r <= v.cells[3]; r++ {
+			for c := v.cells[0]; c <= v.cells[2]; c++ {
+				g.cells[r*g.columns+c] = append(g.cells[r*g.columns+c], v)
+			}
+		}
+	}
+	return g
+}
+
+// add places v in the cells overlapped by its triangle, after the nodes of lower order.
+func (this *triangleGrid) add(v *SpatialNode) {
+	if this.columns == 0 {
+		return
+	}
+	v.cells = this.cellRange(v.t)
+	for r := v.cells[1]; r <= v.cells[3]; r++ {
+		for c := v.cells[0]; c <= v.cells[2]; c++ {
+			list := this.cells[r*this.columns+c]
+			k := len(list)
+			for k > 0 && list[k-1].order > v.order {
+				k--
+			}
+			list = append(list, nil)
+			copy(list[k+1:], list[k:])
+			list[k] = v
+			this.cells[r*this.columns+c] = list
+		}
+	}
+}
+
+// remove takes v out of the cells it was placed in.
+func (this *triangleGrid) remove(v *SpatialNode) {
+	if this.columns == 0 {
+		return
+	}
+	for r := v.cells[1]; r <= v.cells[3]; r++ {
+		for c := v.cells[0]; c <= v.cells[2]; c++ {
+			list := this.cells[r*this.columns+c]
+			for k := 0; k < len(list); k++ {
+				if list[k] == v {
+					this.cells[r*this.columns+c] = append(list[:k], list[k+1:]...)
+					break
+				}
+			}
+		}
+	}
+}
+
+// cellRange returns the first column and row and the last column and row of the cells
+// overlapped by the bounding box of t.
+func (this *triangleGrid) cellRange(t *Triangle) [4]int {
+	tp := t.points
+	c0, r0 := this.cell(math.Min(tp[0].x, math.Min(tp[1].x, tp[2].x)), math.Min(tp[0].y, math.Min(tp[1].y, tp[2].y)))
+	c1, r1 := this.cell(math.Max(tp[0].x, math.Max(tp[1].x, tp[2].x)), math.Max(tp[0].y, math.Max(tp[1].y, tp[2].y)))
+	return [4]int{c0, r0, c1, r1}
+}
+
+// cell returns the column and row of the cell holding x,y, clamped to the grid.
+func (this *triangleGrid) cell(x, y float64) (int, int) {
+	c := int(math.Floor((x - this.minX) / this.size))
+	r := int(math.Floor((y - this.minY) / this.size))
+	return minInt(maxInt(c, 0), this.columns-1), minInt(maxInt(r, 0), this.rows-1)
+}
+
+// candidates returns the nodes whose bounding box may hold p, in the order of the graph.
+func (this *triangleGrid) candidates(p *Point) []*SpatialNode {
+	if this.columns == 0 {
+		return nil
+	}
+	c, r := this.cell(p.x, p.y)
+	return this.cells[r*this.columns+c]
+}
@@ -0,0 +1,63 @@
+package poly2tri
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// scanTriangleAtPoint is GetTriangleAtPoint without the grid.
+func scanTriangleAtPoint(as *AStar, p *Point) *SpatialNode {
+	for i := 0; i < len(as.spatials); i++ {
+		if as.spatials[i].pointInsideTriangle(p, as.predicates) {
+			return as.spatials[i]
+		}
+	}
+	return nil
+}
+
+// checkGrid fails t when the grid of as does not find the triangles of a linear scan, at
+// random points and at the vertices, where several triangles hold the point.
+func checkGrid(t *testing.T, as *AStar, r *rand.Rand) {
+	t.Helper()
+	points := []*Point{}
+	for i := 0; i < 500; i++ {
+		points = append(points, NewPoint64(r.Float64()*110-5, r.Float64()*105-5))
+	}
+	for i := 0; i < len(as.spatials); i++ {
+		points = append(points, as.spatials[i].t.points[0])
+	}
+	for i := 0; i < len(points); i++ {
+		if got, want := as.GetTriangleAtPoint(points[i]), scanTriangleAtPoint(as, points[i]); got != want {
+			t.Fatalf("got %v at %v, want %v", got, points[i], want)
+		}
+	}
+}
+
+func TestTriangleGrid(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	contour, hole, points := insertionPolygon(6)
+	tcx := triangulated(t, contour, hole, points[:30])
+	if err := tcx.Refine(RefineOptions{MaxArea: 50}); err != nil {
+		t.Fatal(err)
+	}
+	as := &AStar{}
+	tcx.AttachAStar(as)
+	checkGrid(t, as, r)
+	// The edits patch the grid
+	for i := 30; i < len(points); i++ {
+		if err := tcx.InsertPoint(points[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tcx.InsertConstraint(NewPoint64(5, 10), NewPoint64(95, 12)); err != nil {
+		t.Fatal(err)
+	}
+	checkGrid(t, as, r)
+	if err := tcx.RemoveConstraint(NewPoint64(5, 10), NewPoint64(95, 12)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tcx.RemoveVertex(points[40]); err != nil {
+		t.Fatal(err)
+	}
+	checkGrid(t, as, r)
+}
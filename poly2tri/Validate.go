@@ -0,0 +1,386 @@
+package poly2tri
+
+import (
+	"sort"
+	"strconv"
+)
+
+// IssueKind is the kind of problem found in the input of a triangulation.
+type IssueKind int
+
+const (
+	// DuplicateVertex is a vertex repeating the previous one of its ring.
+	DuplicateVertex IssueKind = iota
+	// RepeatedClosingVertex is a vertex at the end of its ring repeating the first one.
+	RepeatedClosingVertex
+	// ZeroAreaRing is a ring with less than 3 distinct vertices or with all of them collinear.
+	ZeroAreaRing
+	// SelfIntersection is a ring crossing or touching itself.
+	SelfIntersection
+	// HoleOutside is a hole not strictly inside the contour.
+	HoleOutside
+	// OverlappingHoles are two holes crossing, touching or nested.
+	OverlappingHoles
+	// SteinerOutside is a Steiner point outside the contour, inside a hole or on a ring.
+	SteinerOutside
+	// DuplicatePoint is a Steiner point with the same coordinates as another input point.
+	DuplicatePoint
+)
+
+var issueKindNames = []string{
+	"duplicate vertex",
+	"repeated closing vertex",
+	"zero area ring",
+	"self intersection",
+	"hole outside contour",
+	"overlapping holes",
+	"steiner point outside",
+	"duplicate point",
+}
+
+func (this IssueKind) String() string {
+	if int(this) < len(issueKindNames) {
+		return issueKindNames[this]
+	}
+	return "IssueKind(" + strconv.Itoa(int(this)) + ")"
+}
+
+// Location is a vertex of the input: Ring is 0 for the contour, i+1 for hole i and -1
+// for the Steiner points, Index is the position of the vertex in its ring or list.
+// For an edge, Index is the position of its first vertex.
+type Location struct {
+	Ring  int
+	Index int
+}
+
+// Issue is one problem of the input, Fixed tells if Sanitize repaired it.
+type Issue struct {
+	Kind      IssueKind
+	Locations []Location
+	Points    []*Point
+	Fixed     bool
+}
+
+func (this *Issue) String() string {
+	str := this.Kind.String()
+	for i := 0; i < len(this.Locations); i++ {
+		str += " ring " + strconv.Itoa(this.Locations[i].Ring) + " index " + strconv.Itoa(this.Locations[i].Index)
+		if i < len(this.Points) {
+			str += " (" + this.Points[i].toString() + ")"
+		}
+	}
+	return str
+}
+
+type ValidationReport struct {
+	Issues []*Issue
+}
+
+// Valid tells if the input can be triangulated as is.
+func (this *ValidationReport) Valid() bool {
+	return len(this.Issues) == 0
+}
+
+// Safe tells if the sanitized input can be triangulated, every issue has been fixed.
+func (this *ValidationReport) Safe() bool {
+	for i := 0; i < len(this.Issues); i++ {
+		if !this.Issues[i].Fixed {
+			return false
+		}
+	}
+	return true
+}
+
+// Polygon is the input of a SweepContext: a contour, its holes and the Steiner points.
+// Predicates selects the arithmetic of the checks, RobustPredicates by default.
+type Polygon struct {
+	Contour    []*Point
+	Holes      [][]*Point
+	Points     []*Point
+	Predicates Predicates
+}
+
+// Validate lists the problems that make the triangulation fail or produce garbage.
+// The polygon is not modified.
+func (this *Polygon) Validate() *ValidationReport {
+	_, report := this.check(false)
+	return report
+}
+
+// Sanitize validates the polygon and returns a copy without the problems that can be fixed:
+// repeated vertices are removed, and so are degenerate, crossing or misplaced holes and
+// misplaced Steiner points. A self intersecting or degenerate contour can't be fixed.
+// The points themselves are shared with the polygon, only the slices are new.
+func (this *Polygon) Sanitize() (*Polygon, *ValidationReport) {
+	return this.check(true)
+}
+
+// Init starts a new triangulation of the polygon in tcx.
+func (this *Polygon) Init(tcx *SweepContext) {
+	tcx.Init(this.Contour)
+	tcx.AddHoles(this.Holes)
+	tcx.AddPoints(this.Points)
+}
+
+// validRing is a ring without repeated vertices, index gives the position of each vertex in the input ring.
+type validRing struct {
+	points     []*Point
+	index      []int
+	degenerate bool
+	outside    bool
+	dropped    bool
+}
+
+type validSegment struct {
+	ring       int
+	i          int
+	a, b       *Point
+	xmin, xmax float64
+}
+
+func (this *Polygon) check(fix bool) (*Polygon, *ValidationReport) {
+	report := &ValidationReport{}
+	add := func(kind IssueKind, fixed bool, locations []Location, points []*Point) {
+		report.Issues = append(report.Issues, &Issue{kind, locations, points, fixed})
+	}
+	rings := []*validRing{}
+	input := append([][]*Point{this.Contour}, this.Holes...)
+	// Repeated vertices
+	for r := 0; r < len(input); r++ {
+		ring := &validRing{}
+		points := input[r]
+		length := len(points)
+		for length > 1 && points[length-1].equals(points[0]) {
+			length--
+		}
+		for i := 0; i < length; i++ {
+			if i > 0 && points[i].equals(points[i-1]) {
+				add(DuplicateVertex, true, []Location{{r, i - 1}, {r, i}}, []*Point{points[i-1], points[i]})
+				continue
+			}
+			ring.points = append(ring.points, points[i])
+			ring.index = append(ring.index, i)
+		}
+		for i := length; i < len(points); i++ {
+			add(RepeatedClosingVertex, true, []Location{{r, i}, {r, 0}}, []*Point{points[i], points[0]})
+		}
+		rings = append(rings, ring)
+	}
+	// Degenerate rings
+	for r := 0; r < len(rings); r++ {
+		ring := rings[r]
+		if !this.Predicates.ringHasArea(ring.points) {
+			ring.degenerate = true
+			ring.dropped = r > 0
+			add(ZeroAreaRing, fix && r > 0, []Location{{r, 0}}, ring.points[:minInt(len(ring.points), 1)])
+		}
+	}
+	// Crossings, checked for every pair of segments whose x ranges overlap
+	segments := []*validSegment{}
+	for r := 0; r < len(rings); r++ {
+		ring := rings[r]
+		if ring.degenerate {
+			continue
+		}
+		length := len(ring.points)
+		for i := 0; i < length; i++ {
+			a, b := ring.points[i], ring.points[(i+1)%length]
+			segments = append(segments, &validSegment{r, i, a, b, minFloat(a.x, b.x), maxFloat(a.x, b.x)})
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].xmin < segments[j].xmin
+	})
+	crossing := make(map[[2]int]bool)
+	for i := 0; i < len(segments); i++ {
+		s1 := segments[i]
+		for j := i + 1; j < len(segments) && segments[j].xmin <= s1.xmax; j++ {
+			s2 := segments[j]
+			if !this.Predicates.segmentsTouch(s1, s2, len(rings[s1.ring].points)) {
+				continue
+			}
+			a, b := s1, s2
+			if a.ring > b.ring || (a.ring == b.ring && a.i > b.i) {
+				a, b = b, a
+			}
+			r1, r2 := a.ring, b.ring
+			locations := []Location{{r1, rings[r1].index[a.i]}, {r2, rings[r2].index[b.i]}}
+			points := []*Point{a.a, b.a}
+			if r1 == r2 {
+				rings[r1].dropped = rings[r1].dropped || (fix && r1 > 0)
+				add(SelfIntersection, fix && r1 > 0, locations, points)
+			} else if r1 == 0 {
+				rings[r2].outside = true
+				rings[r2].dropped = rings[r2].dropped || fix
+				add(HoleOutside, fix, locations, points)
+			} else {
+				crossing[[2]int{r1, r2}] = true
+				add(OverlappingHoles, fix, locations, points)
+			}
+		}
+	}
+	contour := rings[0]
+	contourValid := !contour.degenerate
+	// Misplaced holes, each hole is checked once against the contour and the previous holes
+	for r := 1; r < len(rings); r++ {
+		hole := rings[r]
+		if hole.degenerate || hole.outside || (fix && hole.dropped) {
+			continue
+		}
+		if contourValid && this.Predicates.pointInRing(hole.points[0], contour.points) < 0 {
+			hole.outside = true
+			hole.dropped = hole.dropped || fix
+			add(HoleOutside, fix, []Location{{r, hole.index[0]}}, []*Point{hole.points[0]})
+			continue
+		}
+		for o := 1; o < r; o++ {
+			other := rings[o]
+			if other.degenerate || other.outside || (fix && other.dropped) {
+				continue
+			}
+			if crossing[[2]int{o, r}] {
+				if fix && !hole.dropped {
+					hole.dropped = true
+				}
+				continue
+			}
+			if this.Predicates.pointInRing(hole.points[0], other.points) > 0 || this.Predicates.pointInRing(other.points[0], hole.points) > 0 {
+				hole.dropped = hole.dropped || fix
+				add(OverlappingHoles, fix, []Location{{o, other.index[0]}, {r, hole.index[0]}}, []*Point{other.points[0], hole.points[0]})
+				break
+			}
+		}
+	}
+	// Steiner points
+	seen := make(map[[2]float64]bool)
+	for r := 0; r < len(rings); r++ {
+		for i := 0; i < len(rings[r].points); i++ {
+			seen[[2]float64{rings[r].points[i].x, rings[r].points[i].y}] = true
+		}
+	}
+	points := []*Point{}
+	for i := 0; i < len(this.Points); i++ {
+		p := this.Points[i]
+		key := [2]float64{p.x, p.y}
+		if seen[key] {
+			add(DuplicatePoint, fix, []Location{{-1, i}}, []*Point{p})
+			continue
+		}
+		seen[key] = true
+		outside := contourValid && this.Predicates.pointInRing(p, contour.points) <= 0
+		for r := 1; r < len(rings) && !outside; r++ {
+			if !rings[r].degenerate && !(fix && rings[r].dropped) && this.Predicates.pointInRing(p, rings[r].points) >= 0 {
+				outside = true
+			}
+		}
+		if outside {
+			add(SteinerOutside, fix, []Location{{-1, i}}, []*Point{p})
+			continue
+		}
+		points = append(points, p)
+	}
+	if !fix {
+		return nil, report
+	}
+	polygon := &Polygon{Contour: contour.points, Points: points, Predicates: this.Predicates}
+	for r := 1; r < len(rings); r++ {
+		if !rings[r].dropped {
+			polygon.Holes = append(polygon.Holes, rings[r].points)
+		}
+	}
+	return polygon, report
+}
+
+// ringHasArea tells if the ring has at least 3 points that are not collinear.
+func (this Predicates) ringHasArea(points []*Point) bool {
+	for i := 2; i < len(points); i++ {
+		if this.orientation(points[0], points[1], points[i]) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentsTouch tells if two segments have a point in common,
+// other than the vertex shared by two consecutive segments of a ring.
+func (this Predicates) segmentsTouch(s1, s2 *validSegment, length int) bool {
+	a, b, c, d := s1.a, s1.b, s2.a, s2.b
+	if maxFloat(a.y, b.y) < minFloat(c.y, d.y) || maxFloat(c.y, d.y) < minFloat(a.y, b.y) {
+		return false
+	}
+	if s1.ring == s2.ring {
+		if (s1.i+1)%length == s2.i {
+			// Consecutive segments b == c only overlap if they fold back
+			return this.orientation(a, b, d) == 0 && (onSegment(a, b, d) || onSegment(c, d, a))
+		}
+		if (s2.i+1)%length == s1.i {
+			return this.orientation(c, d, b) == 0 && (onSegment(c, d, b) || onSegment(a, b, c))
+		}
+	}
+	o1 := this.orientation(a, b, c)
+	o2 := this.orientation(a, b, d)
+	o3 := this.orientation(c, d, a)
+	o4 := this.orientation(c, d, b)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	return (o1 == 0 && onSegment(a, b, c)) || (o2 == 0 && onSegment(a, b, d)) ||
+		(o3 == 0 && onSegment(c, d, a)) || (o4 == 0 && onSegment(c, d, b))
+}
+
+// onSegment tells if p, known to be collinear with a and b, lies on the closed segment a-b.
+func onSegment(a, b, p *Point) bool {
+	return p.x >= minFloat(a.x, b.x) && p.x <= maxFloat(a.x, b.x) && p.y >= minFloat(a.y, b.y) && p.y <= maxFloat(a.y, b.y)
+}
+
+// pointInRing returns 1 if p is inside the ring, 0 if it is on its border and -1 if it is outside.
+func (this Predicates) pointInRing(p *Point, ring []*Point) int {
+	winding := 0
+	length := len(ring)
+	for i := 0; i < length; i++ {
+		a, b := ring[i], ring[(i+1)%length]
+		o := this.orientation(a, b, p)
+		if o == 0 && p.x >= minFloat(a.x, b.x) && p.x <= maxFloat(a.x, b.x) && p.y >= minFloat(a.y, b.y) && p.y <= maxFloat(a.y, b.y) {
+			return 0
+		}
+		if a.y <= p.y {
+			if b.y > p.y && o > 0 {
+				winding++
+			}
+		} else if b.y <= p.y && o < 0 {
+			winding--
+		}
+	}
+	if winding != 0 {
+		return 1
+	}
+	return -1
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
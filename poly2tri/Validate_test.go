@@ -0,0 +1,57 @@
+package poly2tri
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	p := NewPoint64
+	square := func() []*Point {
+		return rectangle(0, 0, 10, 10)
+	}
+	cases := []struct {
+		name    string
+		polygon *Polygon
+		kinds   []IssueKind
+		safe    bool
+	}{
+		{"valid", &Polygon{Contour: square(), Holes: [][]*Point{{p(4, 4), p(4, 6), p(6, 6)}}, Points: []*Point{p(1, 1)}}, nil, true},
+		{"duplicate", &Polygon{Contour: []*Point{p(0, 0), p(10, 0), p(10, 0), p(10, 10), p(0, 10), p(0, 0)}}, []IssueKind{DuplicateVertex, RepeatedClosingVertex}, true},
+		{"closing twice", &Polygon{Contour: []*Point{p(0, 0), p(10, 0), p(10, 10), p(0, 0), p(0, 0)}}, []IssueKind{RepeatedClosingVertex, RepeatedClosingVertex}, true},
+		{"closing hole", &Polygon{Contour: square(), Holes: [][]*Point{{p(4, 4), p(4, 4), p(4, 6), p(6, 6), p(4, 4), p(4, 4), p(4, 4)}}}, []IssueKind{DuplicateVertex, RepeatedClosingVertex, RepeatedClosingVertex, RepeatedClosingVertex}, true},
+		{"zero area", &Polygon{Contour: square(), Holes: [][]*Point{{p(1, 1), p(2, 2), p(3, 3)}}}, []IssueKind{ZeroAreaRing}, true},
+		{"bowtie", &Polygon{Contour: []*Point{p(0, 0), p(10, 10), p(10, 0), p(0, 10)}}, []IssueKind{SelfIntersection}, false},
+		{"bowtie hole", &Polygon{Contour: square(), Holes: [][]*Point{{p(1, 1), p(3, 3), p(3, 1), p(1, 3)}}}, []IssueKind{SelfIntersection}, true},
+		{"hole outside", &Polygon{Contour: square(), Holes: [][]*Point{{p(11, 11), p(12, 11), p(12, 12)}}}, []IssueKind{HoleOutside}, true},
+		{"hole crossing", &Polygon{Contour: square(), Holes: [][]*Point{{p(8, 8), p(12, 8), p(12, 9)}}}, []IssueKind{HoleOutside, HoleOutside}, true},
+		{"nested holes", &Polygon{Contour: square(), Holes: [][]*Point{rectangle(1, 1, 5, 5), {p(2, 2), p(3, 2), p(3, 3)}}}, []IssueKind{OverlappingHoles}, true},
+		{"steiner points", &Polygon{Contour: square(), Holes: [][]*Point{{p(4, 4), p(4, 6), p(6, 6)}}, Points: []*Point{p(11, 1), p(4.5, 5.5), p(0, 5), p(1, 1), p(1, 1)}}, []IssueKind{SteinerOutside, SteinerOutside, SteinerOutside, DuplicatePoint}, true},
+	}
+	for i := 0; i < len(cases); i++ {
+		c := cases[i]
+		report := c.polygon.Validate()
+		kinds := []IssueKind{}
+		for j := 0; j < len(report.Issues); j++ {
+			kinds = append(kinds, report.Issues[j].Kind)
+		}
+		if len(kinds) != len(c.kinds) || (len(kinds) > 0 && !reflect.DeepEqual(kinds, c.kinds)) {
+			t.Errorf("%s: got issues %v, want %v", c.name, kinds, c.kinds)
+		}
+		clean, report := c.polygon.Sanitize()
+		if report.Safe() != c.safe {
+			t.Errorf("%s: got safe %v, want %v", c.name, report.Safe(), c.safe)
+		}
+		if !c.safe {
+			continue
+		}
+		if report := clean.Validate(); !report.Valid() {
+			t.Errorf("%s: sanitized polygon still has issues %v", c.name, report.Issues)
+		}
+		tcx := &SweepContext{}
+		clean.Init(tcx)
+		if err := tcx.TriangulateE(); err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		}
+	}
+}
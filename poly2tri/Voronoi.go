@@ -0,0 +1,174 @@
+package poly2tri
+
+// Voronoi is the dual of a triangulation: the circumcenters of the triangles are its
+// vertices and two adjacent triangles give the edge between their circumcenters. It is
+// the Voronoi diagram of the points when the triangles are Delaunay, like the ones of
+// DelaunayPoints or of SweepContext.Refine with ConformingDelaunay.
+type Voronoi struct {
+	// Sites holds the points of the triangles, each once.
+	Sites []*Point
+	// Vertices holds the circumcenter of each triangle, in the order of the triangles.
+	Vertices []*Point
+	// Edges holds one edge for each pair of adjacent triangles.
+	Edges     []*VoronoiEdge
+	neighbors map[*Point][]*Point
+}
+
+// VoronoiEdge goes from the circumcenter A of a triangle to the circumcenter B of its
+// neighbor across the side P-Q, and bounds the cells of P and Q.
+type VoronoiEdge struct {
+	A *Point
+	B *Point
+	P *Point
+	Q *Point
+}
+
+// VoronoiCell is the part of the clipping area closer to Site than to the other sites.
+type VoronoiCell struct {
+	Site *Point
+	// Rings are to be filled with the even-odd rule. A cell clipped to a box is a single
+	// counterclockwise ring, and clipping to a polygon keeps the orientation of its rings.
+	Rings [][]*Point
+}
+
+// NewVoronoi builds the Voronoi diagram dual to triangles. Sides without a neighbor in
+// triangles give no edge; the cells of their points are closed by the clipping.
+func NewVoronoi(triangles []*Triangle) *Voronoi {
+	v := &Voronoi{}
+	v.Sites = []*Point{}
+	v.Vertices = make([]*Point, len(triangles))
+	v.Edges = []*VoronoiEdge{}
+	v.neighbors = make(map[*Point][]*Point)
+	index := make(map[*Triangle]int)
+	for i := 0; i < len(triangles); i++ {
+		index[triangles[i]] = i
+		v.Vertices[i] = triangles[i].circumcenter()
+	}
+	for i := 0; i < len(triangles); i++ {
+		t := triangles[i]
+		for j := 0; j < 3; j++ {
+			p, q := t.points[(j+1)%3], t.points[(j+2)%3]
+			v.addNeighbor(p, q)
+			v.addNeighbor(q, p)
+			if k, ok := index[t.neighbors[j]]; ok && k > i {
+				v.Edges = append(v.Edges, &VoronoiEdge{v.Vertices[i], v.Vertices[k], p, q})
+			}
+		}
+	}
+	return v
+}
+
+func (this *Voronoi) addNeighbor(p, q *Point) {
+	list, ok := this.neighbors[p]
+	if !ok {
+		this.Sites = append(this.Sites, p)
+	}
+	for i := 0; i < len(list); i++ {
+		if list[i] == q {
+			return
+		}
+	}
+	this.neighbors[p] = append(list, q)
+}
+
+// CellsInBox returns the cell of each site clipped to the box min-max.
+func (this *Voronoi) CellsInBox(min, max *Point) []*VoronoiCell {
+	cells := []*VoronoiCell{}
+	for i := 0; i < len(this.Sites); i++ {
+		cell := &VoronoiCell{this.Sites[i], [][]*Point{}}
+		if polygon := this.cell(this.Sites[i], min, max); len(polygon) >= 3 {
+			cell.Rings = append(cell.Rings, polygon)
+		}
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// CellsInPolygon returns the cell of each site clipped to the polygon made of rings,
+// filled with the even-odd rule like a contour and its holes.
+func (this *Voronoi) CellsInPolygon(rings [][]*Point) []*VoronoiCell {
+	cells := []*VoronoiCell{}
+	if len(rings) == 0 || len(rings[0]) == 0 {
+		return cells
+	}
+	min := NewPoint64(rings[0][0].x, rings[0][0].y)
+	max := NewPoint64(rings[0][0].x, rings[0][0].y)
+	for i := 0; i < len(rings); i++ {
+		for j := 0; j < len(rings[i]); j++ {
+			p := rings[i][j]
+			min.x, min.y = minFloat(min.x, p.x), minFloat(min.y, p.y)
+			max.x, max.y = maxFloat(max.x, p.x), maxFloat(max.y, p.y)
+		}
+	}
+	for i := 0; i < len(this.Sites); i++ {
+		cell := &VoronoiCell{this.Sites[i], [][]*Point{}}
+		polygon := this.cell(this.Sites[i], min, max)
+		for j := 0; j < len(rings) && len(polygon) >= 3; j++ {
+			ring := rings[j]
+			for k := 0; k < len(polygon) && len(ring) >= 3; k++ {
+				a, b := polygon[k], polygon[(k+1)%len(polygon)]
+				// Keep the left of a-b, the inside of the counterclockwise cell
+				ring = clipHalfPlane(ring, a, b.y-a.y, a.x-b.x)
+			}
+			if len(ring) >= 3 {
+				cell.Rings = append(cell.Rings, ring)
+			}
+		}
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// cell returns the counterclockwise cell of site inside the box min-max, cutting the box
+// with the bisector of site and each of its neighbors.
+func (this *Voronoi) cell(site, min, max *Point) []*Point {
+	polygon := []*Point{NewPoint64(min.x, min.y), NewPoint64(max.x, min.y), NewPoint64(max.x, max.y), NewPoint64(min.x, max.y)}
+	neighbors := this.neighbors[site]
+	for i := 0; i < len(neighbors) && len(polygon) >= 3; i++ {
+		q := neighbors[i]
+		middle := NewPoint64((site.x+q.x)/2, (site.y+q.y)/2)
+		polygon = clipHalfPlane(polygon, middle, q.x-site.x, q.y-site.y)
+	}
+	return polygon
+}
+
+// clipHalfPlane keeps the part of polygon on the side of o opposite to the direction nx,ny
+// (Sutherland-Hodgman). Points of polygon inside are kept as they are.
+func clipHalfPlane(polygon []*Point, o *Point, nx, ny float64) []*Point {
+	result := []*Point{}
+	for i := 0; i < len(polygon); i++ {
+		a, b := polygon[i], polygon[(i+1)%len(polygon)]
+		da := (a.x-o.x)*nx + (a.y-o.y)*ny
+		db := (b.x-o.x)*nx + (b.y-o.y)*ny
+		if da <= 0 {
+			result = append(result, a)
+		}
+		if (da < 0 && db > 0) || (da > 0 && db < 0) {
+			t := da / (da - db)
+			result = append(result, NewPoint64(a.x+(b.x-a.x)*t, a.y+(b.y-a.y)*t))
+		}
+	}
+	return result
+}
+
+// VoronoiCells returns the Voronoi cells of the points of the triangulation, clipped to
+// the triangulated area, whatever its fill rule, through its BoundaryLoops. The cells
+// come from the Delaunay triangulation of the points, since the constrained one misses
+// the neighbors across holes and concave parts of the contour.
+func (this *SweepContext) VoronoiCells() ([]*VoronoiCell, error) {
+	points := []*Point{}
+	seen := make(map[*Point]bool)
+	for i := 0; i < len(this.triangles); i++ {
+		for j := 0; j < 3; j++ {
+			if p := this.triangles[i].points[j]; !seen[p] {
+				seen[p] = true
+				points = append(points, p)
+			}
+		}
+	}
+	triangles, err := DelaunayPoints(points)
+	if err != nil {
+		return nil, err
+	}
+	return NewVoronoi(triangles).CellsInPolygon(BoundaryLoops(this.triangles)), nil
+}
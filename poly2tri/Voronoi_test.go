@@ -0,0 +1,132 @@
+package poly2tri
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// voronoiSites returns the Delaunay triangulation of random points in the 10x10 square.
+func voronoiSites(t *testing.T) []*Triangle {
+	r := rand.New(rand.NewSource(11))
+	points := []*Point{}
+	for i := 0; i < 40; i++ {
+		points = append(points, NewPoint64(r.Float64()*10, r.Float64()*10))
+	}
+	triangles, err := DelaunayPoints(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return triangles
+}
+
+func distance(a, b *Point) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+func TestVoronoiVertices(t *testing.T) {
+	triangles := voronoiSites(t)
+	v := NewVoronoi(triangles)
+	for i := 0; i < len(triangles); i++ {
+		c, tp := v.Vertices[i], triangles[i].points
+		if d := distance(c, tp[0]); math.Abs(distance(c, tp[1])-d) > 1e-9 || math.Abs(distance(c, tp[2])-d) > 1e-9 {
+			t.Errorf("vertex %v is not the circumcenter of %v", c, tp)
+		}
+	}
+	for i := 0; i < len(v.Edges); i++ {
+		e := v.Edges[i]
+		// Both ends are the circumcenters of triangles with the side P-Q
+		if math.Abs(distance(e.A, e.P)-distance(e.A, e.Q)) > 1e-9 || math.Abs(distance(e.B, e.P)-distance(e.B, e.Q)) > 1e-9 {
+			t.Errorf("edge %v %v is not on the bisector of %v %v", e.A, e.B, e.P, e.Q)
+		}
+	}
+	// The corners of the cells inside the box are circumcenters of the triangles around the site
+	cells := v.CellsInBox(NewPoint64(-100, -100), NewPoint64(100, 100))
+	for i := 0; i < len(cells); i++ {
+		ring := cells[i].Rings[0]
+		for j := 0; j < len(ring); j++ {
+			p := ring[j]
+			if math.Abs(p.x) == 100 || math.Abs(p.y) == 100 {
+				continue
+			}
+			found := false
+			for k := 0; k < len(triangles) && !found; k++ {
+				found = triangles[k].containsPoint(cells[i].Site) && distance(p, v.Vertices[k]) < 1e-6
+			}
+			if !found {
+				t.Errorf("corner %v of the cell of %v is not a circumcenter around it", p, cells[i].Site)
+			}
+		}
+	}
+}
+
+func TestVoronoiCellsInBox(t *testing.T) {
+	v := NewVoronoi(voronoiSites(t))
+	cells := v.CellsInBox(NewPoint64(0, 0), NewPoint64(10, 10))
+	if len(cells) != 40 {
+		t.Fatalf("got %d cells, want 40", len(cells))
+	}
+	area := 0.0
+	for i := 0; i < len(cells); i++ {
+		if len(cells[i].Rings) != 1 {
+			t.Fatalf("the cell of %v has %d rings", cells[i].Site, len(cells[i].Rings))
+		}
+		ring := cells[i].Rings[0]
+		if ringArea(ring) <= 0 {
+			t.Errorf("the cell of %v is not counterclockwise", cells[i].Site)
+		}
+		if RobustPredicates.pointInRing(cells[i].Site, ring) < 0 {
+			t.Errorf("the cell of %v does not hold it", cells[i].Site)
+		}
+		for j := 0; j < len(ring); j++ {
+			if p := ring[j]; p.x < -1e-9 || p.x > 10+1e-9 || p.y < -1e-9 || p.y > 10+1e-9 {
+				t.Errorf("the cell of %v leaves the box at %v", cells[i].Site, p)
+			}
+		}
+		area += ringArea(ring)
+	}
+	if math.Abs(area-100) > 1e-9 {
+		t.Errorf("got area %v, want 100", area)
+	}
+}
+
+func TestVoronoiCellsInPolygon(t *testing.T) {
+	v := NewVoronoi(voronoiSites(t))
+	hole := rectangle(3, 3, 7, 6)
+	for i, j := 0, len(hole)-1; i < j; i, j = i+1, j-1 {
+		hole[i], hole[j] = hole[j], hole[i]
+	}
+	cells := v.CellsInPolygon([][]*Point{rectangle(0, 0, 10, 10), hole})
+	area := 0.0
+	for i := 0; i < len(cells); i++ {
+		for j := 0; j < len(cells[i].Rings); j++ {
+			// The clipped hole stays clockwise and takes its area off
+			area += ringArea(cells[i].Rings[j])
+		}
+	}
+	if math.Abs(area-88) > 1e-9 {
+		t.Errorf("got area %v, want 88", area)
+	}
+	// Each point of the polygon is in one cell, and the points of the hole in none
+	r := rand.New(rand.NewSource(12))
+	for k := 0; k < 2000; k++ {
+		p := NewPoint64(r.Float64()*10, r.Float64()*10)
+		count := 0
+		for i := 0; i < len(cells); i++ {
+			inside := 0
+			for j := 0; j < len(cells[i].Rings); j++ {
+				if RobustPredicates.pointInRing(p, cells[i].Rings[j]) > 0 {
+					inside++
+				}
+			}
+			count += inside % 2
+		}
+		want := 1
+		if p.x > 3 && p.x < 7 && p.y > 3 && p.y < 6 {
+			want = 0
+		}
+		if count != want {
+			t.Errorf("%v is in %d cells, want %d", p, count, want)
+		}
+	}
+}